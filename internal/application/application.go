@@ -5,10 +5,18 @@ import (
 	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
 	"github.com/stratocraft/stratocraft.dev/internal/site"
 	"log"
+	"time"
 )
 
 type Application struct {
 	ContentManager *contentmanager.ContentManager
+
+	// started is when the server started, so pages rendered by templates
+	// deployed with it aren't considered older than the deploy
+	started time.Time
+
+	// syntaxCSS is the code highlighting stylesheet for the configured themes
+	syntaxCSS []byte
 }
 
 func New() *Application {
@@ -23,12 +31,28 @@ func New() *Application {
 	}
 
 	cm := contentmanager.New(repoOwner, repoName)
+	registerShortcodes(cm)
+	if err := cm.EnableMarkdownExtensions(site.MarkdownExtensions); err != nil {
+		log.Fatalf("Invalid MarkdownExtensions in site.go: %v", err)
+	}
+	if err := cm.TrustSources(site.TrustedSources); err != nil {
+		log.Fatalf("Invalid TrustedSources in site.go: %v", err)
+	}
+	if err := cm.ResponsiveImages(site.ImageWidths, site.ImageSizes, site.ImageCacheDir); err != nil {
+		log.Fatalf("Invalid image settings in site.go: %v", err)
+	}
+	syntaxCSS, err := contentmanager.SyntaxCSS(site.SyntaxThemeLight, site.SyntaxThemeDark)
+	if err != nil {
+		log.Fatalf("Invalid SyntaxThemeLight or SyntaxThemeDark in site.go: %v", err)
+	}
 	if err := cm.RefreshContent(); err != nil {
 		log.Printf("Failed to load initial content: %v", err)
 	}
 
 	return &Application{
 		ContentManager: cm,
+		started:        time.Now(),
+		syntaxCSS:      syntaxCSS,
 	}
 }
 
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+// ArchiveIndex lists the years and months posts were published in, with the
+// number of posts in each
+func (a *Application) ArchiveIndex(c echo.Context) error {
+	years := a.ContentManager.GetArchive()
+
+	return pages.Archive(years).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// ArchiveYear lists the posts published in a year, a page at a time
+func (a *Application) ArchiveYear(c echo.Context) error {
+	return a.renderArchive(c, 0)
+}
+
+// ArchiveMonth lists the posts published in a month, a page at a time
+func (a *Application) ArchiveMonth(c echo.Context) error {
+	month, err := strconv.Atoi(c.Param("month"))
+	if err != nil || month < 1 || month > 12 {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	return a.renderArchive(c, time.Month(month))
+}
+
+// renderArchive lists the posts in a year, or a month of it when month isn't
+// zero
+func (a *Application) renderArchive(c echo.Context, month time.Month) error {
+	number, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	year, exists := a.ContentManager.GetArchiveYear(number)
+	if !exists {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	page, _, ok := pageNumber(c.Param("page"))
+	if !ok {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	// Send other spellings, like /archive/2024/3 or /archive/2024/page/1, to
+	// the canonical URL
+	if canonical := pages.PagedURL(pages.ArchiveURL(year.Year, month))(page); c.Request().URL.Path != canonical {
+		if query := c.QueryString(); query != "" {
+			canonical += "?" + query
+		}
+		return c.Redirect(http.StatusMovedPermanently, canonical)
+	}
+
+	posts := a.ContentManager.Query().
+		Between(contentmanager.ArchiveRange(year.Year, month)).
+		Offset(contentmanager.PageOffset(page, site.PostsPerPage)).
+		Limit(site.PostsPerPage).
+		Page()
+	if posts.Total == 0 || page > posts.TotalPages() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	return pages.ArchivePosts(year, month, posts).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PostAsset serves an image or file referenced from a post in the posts
+// repository. URLs carrying the asset's content hash are cached for a year.
+func (a *Application) PostAsset(c echo.Context) error {
+	slug := c.Param("slug")
+	name := pathParam(c, "*")
+
+	asset, exists := a.ContentManager.GetAsset(slug, name)
+	if !exists {
+		return c.String(http.StatusNotFound, "Asset not found")
+	}
+
+	etag := `"` + asset.Hash + `"`
+
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("X-Content-Type-Options", "nosniff")
+	if path.Ext(asset.Path) == ".svg" {
+		header.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	}
+	if c.QueryParam("v") == asset.Hash {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "public, max-age=3600")
+	}
+
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, asset.ContentType, asset.Data)
+}
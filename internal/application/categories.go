@@ -0,0 +1,63 @@
+package application
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
+	"github.com/stratocraft/stratocraft.dev/internal/views/components"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+// CategoriesIndex lists the category hierarchy with the number of posts in
+// each category
+func (a *Application) CategoriesIndex(c echo.Context) error {
+	categories := a.ContentManager.GetCategories()
+
+	return pages.Categories(categories).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// CategoryPosts lists the posts in a category and its subcategories, a page
+// at a time
+func (a *Application) CategoryPosts(c echo.Context) error {
+	// Category names can't contain a slash, so the path splits cleanly, and
+	// pages after the first end in /page/:page
+	segments := strings.Split(strings.Trim(pathParam(c, "*"), "/"), "/")
+	var pageParam string
+	if n := len(segments); n > 2 && segments[n-2] == "page" {
+		segments, pageParam = segments[:n-2], segments[n-1]
+	}
+	path := contentmanager.Category(segments)
+
+	category, exists := a.ContentManager.GetCategory(path)
+	if !exists {
+		return c.String(http.StatusNotFound, "Category not found")
+	}
+
+	number, redirect, ok := pageNumber(pageParam)
+	if !ok {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	// Send other spellings and /page/1 to the category's canonical URL
+	if canonical := string(components.CategoryURL(category.Path)); redirect || canonical != string(components.CategoryURL(path)) {
+		canonical = pages.PagedURL(canonical)(number)
+		if query := c.QueryString(); query != "" {
+			canonical += "?" + query
+		}
+		return c.Redirect(http.StatusMovedPermanently, canonical)
+	}
+
+	page := a.ContentManager.Query().
+		Category(category.Path).
+		Offset(contentmanager.PageOffset(number, site.PostsPerPage)).
+		Limit(site.PostsPerPage).
+		Page()
+	if page.Total == 0 || number > page.TotalPages() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	return pages.Category(category, page).Render(c.Request().Context(), c.Response().Writer)
+}
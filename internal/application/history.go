@@ -0,0 +1,69 @@
+package application
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+// PostHistory lists the commits that touched a post and shows the changes
+// made by the selected revision
+func (a *Application) PostHistory(c echo.Context) error {
+	slug := c.Param("slug")
+
+	post, exists := a.ContentManager.GetBySlug(slug)
+	if !exists {
+		return c.String(http.StatusNotFound, "Post not found")
+	}
+
+	revisions, err := a.ContentManager.GetHistory(slug)
+	if err != nil {
+		log.Printf("Failed to load history for %s: %v", slug, err)
+		return c.String(http.StatusBadGateway, "Revision history is unavailable right now")
+	}
+
+	if len(revisions) == 0 {
+		return pages.PostHistory(post, revisions, "", "", nil).Render(c.Request().Context(), c.Response().Writer)
+	}
+
+	// Default to the changes made by the latest revision
+	to, from := revisions[0].SHA, ""
+	if sha := c.QueryParam("to"); sha != "" {
+		to = sha
+	}
+
+	toIndex := revisionIndex(revisions, to)
+	if toIndex < 0 {
+		return c.String(http.StatusBadRequest, "Unknown revision")
+	}
+	if toIndex+1 < len(revisions) {
+		from = revisions[toIndex+1].SHA
+	}
+
+	if sha := c.QueryParam("from"); sha != "" {
+		if revisionIndex(revisions, sha) < 0 {
+			return c.String(http.StatusBadRequest, "Unknown revision")
+		}
+		from = sha
+	}
+
+	diff, err := a.ContentManager.GetRevisionDiff(slug, from, to)
+	if err != nil {
+		log.Printf("Failed to diff %s between %s and %s: %v", slug, from, to, err)
+		return c.String(http.StatusBadGateway, "Revision history is unavailable right now")
+	}
+
+	return pages.PostHistory(post, revisions, from, to, diff).Render(c.Request().Context(), c.Response().Writer)
+}
+
+func revisionIndex(revisions []contentmanager.Revision, sha string) int {
+	for i, revision := range revisions {
+		if revision.SHA == sha {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,20 @@
+package application
+
+import "strconv"
+
+// pageNumber reads the page of a listing from the page in its path, as in
+// /tags/go/page/2, counting from 1. A listing's first page has no page in
+// its path, so redirect reports a path that names it anyway, like
+// /tags/go/page/1, or spells the number differently, like /tags/go/page/02.
+// ok is false for a page that isn't a positive number.
+func pageNumber(page string) (number int, redirect bool, ok bool) {
+	if page == "" {
+		return 1, false, true
+	}
+
+	number, err := strconv.Atoi(page)
+	if err != nil || number < 1 {
+		return 0, false, false
+	}
+	return number, number == 1 || strconv.Itoa(number) != page, true
+}
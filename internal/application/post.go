@@ -2,21 +2,56 @@ package application
 
 import (
 	"net/http"
+	"time"
+
 	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
 	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
 )
 
 func (a *Application) PostDetail(c echo.Context) error {
 	slug := c.Param("slug")
-	
+
 	if slug == "" {
 		return c.String(http.StatusBadRequest, "Post slug is required")
 	}
-	
+
 	post, exists := a.ContentManager.GetBySlug(slug)
 	if !exists {
 		return c.String(http.StatusNotFound, "Post not found")
 	}
-	
-	return pages.Post(post).Render(c.Request().Context(), c.Response().Writer)
-} 
\ No newline at end of file
+
+	series, _ := a.ContentManager.GetSeries(post.Series)
+	neighbours := a.ContentManager.GetNeighbours(post, contentmanager.NavigationScope(site.PostNavigationScope))
+	related := a.ContentManager.GetRelated(post.Slug, site.RelatedPosts)
+
+	// Let browsers and caches revalidate against the last revision of the post,
+	// or of the other posts it links to, since their titles are shown. Posts
+	// being published or unpublished changes which posts are linked, and a
+	// deploy can change the templates, so the page is never older than either
+	lastModified := post.Updated
+	for _, changed := range []time.Time{a.ContentManager.Listed(), a.started} {
+		if changed.After(lastModified) {
+			lastModified = changed
+		}
+	}
+	linked := append(append([]contentmanager.Post(nil), series.Posts...), related...)
+	for _, neighbour := range []*contentmanager.Post{neighbours.Prev, neighbours.Next} {
+		if neighbour != nil {
+			linked = append(linked, *neighbour)
+		}
+	}
+	for _, other := range linked {
+		if other.Updated.After(lastModified) {
+			lastModified = other.Updated
+		}
+	}
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if since, err := http.ParseTime(c.Request().Header.Get(echo.HeaderIfModifiedSince)); err == nil && !lastModified.After(since) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	c.Response().Header().Set(echo.HeaderLastModified, lastModified.Format(http.TimeFormat))
+
+	return pages.Post(post, series, neighbours, related).Render(c.Request().Context(), c.Response().Writer)
+}
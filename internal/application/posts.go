@@ -1,13 +1,83 @@
 package application
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
 	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
 )
 
+// PostsList renders the first page of posts, newest first unless the query
+// string asks for another order or filters
 func (a *Application) PostsList(c echo.Context) error {
-	// Get all posts (already sorted by date, newest first)
-	posts := a.ContentManager.GetAll()
-	
-	return pages.Posts(posts).Render(c.Request().Context(), c.Response().Writer)
-} 
\ No newline at end of file
+	return a.renderPostsPage(c, 1)
+}
+
+// PostsPage renders a later page of posts at /posts/page/:page
+func (a *Application) PostsPage(c echo.Context) error {
+	number, redirect, ok := pageNumber(c.Param("page"))
+	if !ok {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	// The first page lives at /posts
+	if redirect {
+		return c.Redirect(http.StatusMovedPermanently, a.postsFilter(c).URL(number))
+	}
+
+	return a.renderPostsPage(c, number)
+}
+
+func (a *Application) renderPostsPage(c echo.Context, number int) error {
+	filter := a.postsFilter(c)
+
+	query := a.ContentManager.Query().SortBy(filter.Sort)
+	if filter.Tag != "" {
+		query = query.Tags(filter.Tag)
+	}
+	if filter.Year != 0 {
+		query = query.Between(contentmanager.ArchiveRange(filter.Year, 0))
+	}
+
+	page := query.Offset(contentmanager.PageOffset(number, site.PostsPerPage)).Limit(site.PostsPerPage).Page()
+	if number > page.TotalPages() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	// htmx requests for a new order or filter only need the results, and the
+	// address bar gets the tidy URL rather than the raw form values
+	c.Response().Header().Add("Vary", "HX-Request")
+	if c.Request().Header.Get("HX-Request") == "true" {
+		c.Response().Header().Set("HX-Push-Url", filter.URL(number))
+		return pages.PostsResults(page, filter).Render(c.Request().Context(), c.Response().Writer)
+	}
+
+	return pages.Posts(page, filter, a.ContentManager.GetTags(), a.ContentManager.GetYears()).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// postsFilter reads the order and filters of the posts listing from the query
+// string, ignoring values it doesn't understand
+func (a *Application) postsFilter(c echo.Context) pages.PostsFilter {
+	filter := pages.PostsFilter{Sort: contentmanager.SortNewest}
+
+	switch order := contentmanager.SortOrder(c.QueryParam("sort")); order {
+	case contentmanager.SortOldest, contentmanager.SortUpdated, contentmanager.SortTitle:
+		filter.Sort = order
+	}
+
+	if tag := c.QueryParam("tag"); tag != "" {
+		filter.Tag = tag
+		if info, ok := a.ContentManager.GetTag(tag); ok {
+			filter.Tag = info.Name
+		}
+	}
+
+	if year, err := strconv.Atoi(c.QueryParam("year")); err == nil && year > 0 {
+		filter.Year = year
+	}
+
+	return filter
+}
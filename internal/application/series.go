@@ -0,0 +1,24 @@
+package application
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/views/components"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+// SeriesPosts lists the parts of a series in order
+func (a *Application) SeriesPosts(c echo.Context) error {
+	series, exists := a.ContentManager.GetSeries(pathParam(c, "name"))
+	if !exists {
+		return c.String(http.StatusNotFound, "Series not found")
+	}
+
+	// Send other spellings to the series' canonical URL
+	if pathParam(c, "name") != series.Name {
+		return c.Redirect(http.StatusMovedPermanently, string(components.SeriesURL(series.Name)))
+	}
+
+	return pages.Series(series).Render(c.Request().Context(), c.Response().Writer)
+}
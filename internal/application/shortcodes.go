@@ -0,0 +1,74 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/a-h/templ"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/views/buttons"
+	"github.com/stratocraft/stratocraft.dev/internal/views/embeds"
+)
+
+var (
+	youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	gistUserPattern  = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	gistIDPattern    = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+	gistFilePattern  = regexp.MustCompile(`^[\w.-]*$`)
+	tweetUserPattern = regexp.MustCompile(`^\w{1,15}$`)
+	tweetIDPattern   = regexp.MustCompile(`^\d+$`)
+)
+
+// registerShortcodes makes the site's embeds available to posts:
+//
+//	{{< youtube dQw4w9WgXcQ "Optional caption" >}}
+//	{{< gist user id file="main.go" >}}
+//	{{< tweet user id "Optional quoted text" >}}
+//	{{< button github >}}
+func registerShortcodes(cm *contentmanager.ContentManager) {
+	cm.RegisterShortcode("youtube", func(args contentmanager.ShortcodeArgs) (templ.Component, error) {
+		id := args.Get("id", 0)
+		if !youtubeIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid YouTube video id %q", id)
+		}
+		return embeds.YouTube(id, args.Get("title", 1), args.Thumbnail), nil
+	})
+	cm.RegisterShortcodeThumbnail("youtube", func(args contentmanager.ShortcodeArgs) (string, bool) {
+		id := args.Get("id", 0)
+		if !youtubeIDPattern.MatchString(id) {
+			return "", false
+		}
+		return "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg", true
+	})
+
+	cm.RegisterShortcode("gist", func(args contentmanager.ShortcodeArgs) (templ.Component, error) {
+		user, id, file := args.Get("user", 0), args.Get("id", 1), args.Get("file", 2)
+		if !gistUserPattern.MatchString(user) || !gistIDPattern.MatchString(id) || !gistFilePattern.MatchString(file) {
+			return nil, fmt.Errorf("invalid gist %q/%q", user, id)
+		}
+		return embeds.Gist(user, id, file), nil
+	})
+
+	cm.RegisterShortcode("tweet", func(args contentmanager.ShortcodeArgs) (templ.Component, error) {
+		user, id := args.Get("user", 0), args.Get("id", 1)
+		if !tweetUserPattern.MatchString(user) || !tweetIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid tweet %q/%q", user, id)
+		}
+		return embeds.Tweet(user, id, args.Get("text", 2)), nil
+	})
+
+	cm.RegisterShortcode("button", func(args contentmanager.ShortcodeArgs) (templ.Component, error) {
+		switch name := args.Get("name", 0); name {
+		case "github":
+			return buttons.GitHub(), nil
+		case "linkedin":
+			return buttons.Linkedin(), nil
+		case "x":
+			return buttons.X(), nil
+		case "youtube":
+			return buttons.YouTube(), nil
+		default:
+			return nil, fmt.Errorf("unknown button %q", name)
+		}
+	})
+}
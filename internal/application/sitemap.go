@@ -0,0 +1,104 @@
+package application
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
+	"github.com/stratocraft/stratocraft.dev/internal/views/components"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// Sitemap renders sitemap.xml for the static pages, tags, categories, archive, series and every published post
+func (a *Application) Sitemap(c echo.Context) error {
+	posts := a.ContentManager.GetAll()
+
+	urlSet := sitemapURLSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+	}
+
+	// The listing pages change whenever any post does
+	var latest time.Time
+	for _, post := range posts {
+		if post.Updated.After(latest) {
+			latest = post.Updated
+		}
+	}
+
+	var listingLastMod string
+	if !latest.IsZero() {
+		listingLastMod = latest.UTC().Format(time.RFC3339)
+	}
+	urlSet.URLs = append(urlSet.URLs,
+		sitemapURL{Loc: site.URL + "/", LastMod: listingLastMod},
+		sitemapURL{Loc: site.URL + "/posts", LastMod: listingLastMod},
+		sitemapURL{Loc: site.URL + "/tags", LastMod: listingLastMod},
+		sitemapURL{Loc: site.URL + "/categories", LastMod: listingLastMod},
+		sitemapURL{Loc: site.URL + "/archive", LastMod: listingLastMod},
+		sitemapURL{Loc: site.URL + "/about"},
+	)
+
+	var addCategories func(categories []contentmanager.CategoryNode)
+	addCategories = func(categories []contentmanager.CategoryNode) {
+		for _, category := range categories {
+			urlSet.URLs = append(urlSet.URLs, sitemapURL{
+				Loc: site.URL + string(components.CategoryURL(category.Path)),
+			})
+			addCategories(category.Children)
+		}
+	}
+	addCategories(a.ContentManager.GetCategories())
+
+	for _, year := range a.ContentManager.GetArchive() {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{Loc: site.URL + pages.ArchiveURL(year.Year, 0)})
+		for _, month := range year.Months {
+			urlSet.URLs = append(urlSet.URLs, sitemapURL{Loc: site.URL + pages.ArchiveURL(month.Year, month.Month)})
+		}
+	}
+
+	for _, tag := range a.ContentManager.GetTags() {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc: site.URL + "/tags/" + url.PathEscape(tag.Name),
+		})
+	}
+
+	// Series names are already spelled the same way in every part
+	series := make(map[string]bool)
+	for _, post := range posts {
+		if post.Series != "" && !series[post.Series] {
+			series[post.Series] = true
+			urlSet.URLs = append(urlSet.URLs, sitemapURL{
+				Loc: site.URL + string(components.SeriesURL(post.Series)),
+			})
+		}
+	}
+
+	for _, post := range posts {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     site.URL + "/posts/" + post.Slug,
+			LastMod: post.Updated.UTC().Format(time.RFC3339),
+		})
+	}
+
+	output, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, append([]byte(xml.Header), output...))
+}
@@ -0,0 +1,13 @@
+package application
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SyntaxCSS serves the code highlighting stylesheet for the configured themes
+func (app *Application) SyntaxCSS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=86400")
+	return c.Blob(http.StatusOK, "text/css; charset=utf-8", app.syntaxCSS)
+}
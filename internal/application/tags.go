@@ -0,0 +1,64 @@
+package application
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stratocraft/stratocraft.dev/internal/contentmanager"
+	"github.com/stratocraft/stratocraft.dev/internal/site"
+	"github.com/stratocraft/stratocraft.dev/internal/views/components"
+	"github.com/stratocraft/stratocraft.dev/internal/views/pages"
+)
+
+// TagsIndex lists every tag with the number of posts that have it
+func (a *Application) TagsIndex(c echo.Context) error {
+	tags := a.ContentManager.GetTags()
+
+	return pages.Tags(tags).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// TagPosts lists the posts with a tag, a page at a time
+func (a *Application) TagPosts(c echo.Context) error {
+	tag, exists := a.ContentManager.GetTag(pathParam(c, "tag"))
+	if !exists {
+		return c.String(http.StatusNotFound, "Tag not found")
+	}
+
+	number, redirect, ok := pageNumber(c.Param("page"))
+	if !ok {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	// Send aliases, other spellings and /page/1 to the canonical page
+	if redirect || pathParam(c, "tag") != tag.Name {
+		target := pages.PagedURL(string(components.TagURL(tag.Name)))(number)
+		if query := c.QueryString(); query != "" {
+			target += "?" + query
+		}
+		return c.Redirect(http.StatusMovedPermanently, target)
+	}
+
+	page := a.ContentManager.Query().
+		Tags(tag.Name).
+		Offset(contentmanager.PageOffset(number, site.PostsPerPage)).
+		Limit(site.PostsPerPage).
+		Page()
+	if page.Total == 0 || number > page.TotalPages() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+
+	return pages.Tag(tag, page).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// pathParam returns a path parameter decoded. Echo leaves parameters escaped
+// when the path contains characters like an encoded slash.
+func pathParam(c echo.Context, name string) string {
+	value := c.Param(name)
+	if c.Request().URL.RawPath != "" {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			return unescaped
+		}
+	}
+	return value
+}
@@ -0,0 +1,227 @@
+package contentmanager
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Abbreviation is a term with an expansion defined elsewhere in the post.
+type Abbreviation struct {
+	ast.BaseInline
+	Term      string
+	Expansion string
+}
+
+// KindAbbreviation is the NodeKind of Abbreviation nodes.
+var KindAbbreviation = ast.NewNodeKind("Abbreviation")
+
+// Kind implements ast.Node.
+func (n *Abbreviation) Kind() ast.NodeKind {
+	return KindAbbreviation
+}
+
+// Dump implements ast.Node.
+func (n *Abbreviation) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Term": n.Term, "Expansion": n.Expansion}, nil)
+}
+
+// AbbreviationDefinition is a "*[HTML]: HyperText Markup Language" line. It
+// renders nothing; its term is marked up wherever it appears in the post.
+type AbbreviationDefinition struct {
+	ast.BaseBlock
+}
+
+// KindAbbreviationDefinition is the NodeKind of AbbreviationDefinition nodes.
+var KindAbbreviationDefinition = ast.NewNodeKind("AbbreviationDefinition")
+
+// Kind implements ast.Node.
+func (n *AbbreviationDefinition) Kind() ast.NodeKind {
+	return KindAbbreviationDefinition
+}
+
+// Dump implements ast.Node.
+func (n *AbbreviationDefinition) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// abbreviationExtension adds PHP Markdown Extra style abbreviations.
+type abbreviationExtension struct{}
+
+func (e *abbreviationExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&abbreviationParser{}, 100)),
+		parser.WithASTTransformers(util.Prioritized(&abbreviationTransformer{}, 200)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&abbreviationRenderer{}, 500)),
+	)
+}
+
+var abbreviationsKey = parser.NewContextKey()
+
+var abbreviationPattern = regexp.MustCompile(`^\*\[([^\]]+)\]:\s*(.*?)\s*$`)
+
+type abbreviationParser struct{}
+
+func (p *abbreviationParser) Trigger() []byte {
+	return []byte{'*'}
+}
+
+func (p *abbreviationParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	match := abbreviationPattern.FindSubmatch(bytes.TrimRight(line, "\r\n"))
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+	reader.Advance(segment.Len() - 1)
+
+	abbreviations, _ := pc.Get(abbreviationsKey).(map[string]string)
+	if abbreviations == nil {
+		abbreviations = make(map[string]string)
+		pc.Set(abbreviationsKey, abbreviations)
+	}
+	abbreviations[strings.TrimSpace(string(match[1]))] = string(match[2])
+
+	return &AbbreviationDefinition{}, parser.NoChildren
+}
+
+func (p *abbreviationParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (p *abbreviationParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *abbreviationParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *abbreviationParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// abbreviationTransformer wraps whole-word occurrences of defined terms in
+// Abbreviation nodes. Code, links' destinations and raw HTML are untouched
+// because only Text nodes are searched.
+type abbreviationTransformer struct{}
+
+func (t *abbreviationTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	abbreviations, _ := pc.Get(abbreviationsKey).(map[string]string)
+	if len(abbreviations) == 0 {
+		return
+	}
+
+	// Prefer the longest term when one is a prefix of another
+	terms := make([]string, 0, len(abbreviations))
+	for term := range abbreviations {
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	pattern := regexp.MustCompile(strings.Join(terms, "|"))
+
+	source := reader.Source()
+
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeSpan, *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML, *Abbreviation:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, node := range texts {
+		splitAbbreviations(node, source, pattern, abbreviations)
+	}
+}
+
+// splitAbbreviations replaces node with text and Abbreviation nodes for each
+// whole-word match of pattern in its segment.
+func splitAbbreviations(node *ast.Text, source []byte, pattern *regexp.Regexp, abbreviations map[string]string) {
+	segment := node.Segment
+	value := segment.Value(source)
+	parent := node.Parent()
+
+	start := 0
+	var prev ast.Node = node
+	for _, loc := range pattern.FindAllIndex(value, -1) {
+		if !isWordBoundary(value, loc[0], loc[1]) {
+			continue
+		}
+
+		if loc[0] > start {
+			before := ast.NewTextSegment(text.NewSegment(segment.Start+start, segment.Start+loc[0]))
+			parent.InsertAfter(parent, prev, before)
+			prev = before
+		}
+
+		term := string(value[loc[0]:loc[1]])
+		abbr := &Abbreviation{Term: term, Expansion: abbreviations[term]}
+		abbr.AppendChild(abbr, ast.NewTextSegment(text.NewSegment(segment.Start+loc[0], segment.Start+loc[1])))
+		parent.InsertAfter(parent, prev, abbr)
+		prev = abbr
+
+		start = loc[1]
+	}
+
+	if prev == node {
+		return
+	}
+
+	// The remainder keeps the original node so its line break flags survive
+	node.Segment = text.NewSegment(segment.Start+start, segment.Stop)
+	parent.RemoveChild(parent, node)
+	parent.InsertAfter(parent, prev, node)
+}
+
+func isWordBoundary(value []byte, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRune(value[:start])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(value) {
+		r, _ := utf8.DecodeRune(value[end:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type abbreviationRenderer struct{}
+
+func (r *abbreviationRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAbbreviation, r.renderAbbreviation)
+	reg.Register(KindAbbreviationDefinition, r.renderDefinition)
+}
+
+func (r *abbreviationRenderer) renderAbbreviation(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		fmt.Fprintf(w, `<abbr title="%s">`, util.EscapeHTML([]byte(node.(*Abbreviation).Expansion)))
+	} else {
+		_, _ = w.WriteString(`</abbr>`)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *abbreviationRenderer) renderDefinition(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,86 @@
+package contentmanager
+
+import "time"
+
+// ArchiveYear is a year with the number of posts published in it, and the
+// months that have posts, newest first.
+type ArchiveYear struct {
+	Year   int
+	Count  int
+	Months []ArchiveMonth
+}
+
+// ArchiveMonth is a month with the number of posts published in it.
+type ArchiveMonth struct {
+	Year  int
+	Month time.Month
+	Count int
+}
+
+// archiveDate is the date a post is filed under in the archive. Dates are
+// compared in UTC, as the archive's date ranges are.
+func archiveDate(post Post) (int, time.Month) {
+	date := post.Date.UTC()
+	return date.Year(), date.Month()
+}
+
+// GetArchive returns the years and months posts were published in, newest
+// first, with the number of posts in each.
+func (cm *ContentManager) GetArchive() []ArchiveYear {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	if cm.index == nil {
+		return nil
+	}
+
+	// The index is newest first, so each year and month is one run of posts
+	var years []ArchiveYear
+	for _, post := range cm.index.posts {
+		year, month := archiveDate(post)
+
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, ArchiveYear{Year: year})
+		}
+		current := &years[len(years)-1]
+		current.Count++
+
+		if len(current.Months) == 0 || current.Months[len(current.Months)-1].Month != month {
+			current.Months = append(current.Months, ArchiveMonth{Year: year, Month: month})
+		}
+		current.Months[len(current.Months)-1].Count++
+	}
+
+	return years
+}
+
+// GetArchiveYear returns a year of the archive, if any posts were published
+// in it.
+func (cm *ContentManager) GetArchiveYear(year int) (ArchiveYear, bool) {
+	for _, archived := range cm.GetArchive() {
+		if archived.Year == year {
+			return archived, true
+		}
+	}
+	return ArchiveYear{}, false
+}
+
+// GetYears returns the years posts were published in, newest first.
+func (cm *ContentManager) GetYears() []int {
+	var years []int
+	for _, archived := range cm.GetArchive() {
+		years = append(years, archived.Year)
+	}
+	return years
+}
+
+// ArchiveRange returns the start of a year, or of a month in it, and the
+// start of the next, for Query.Between. A month of zero is the whole year.
+func ArchiveRange(year int, month time.Month) (time.Time, time.Time) {
+	if month == 0 {
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, 0)
+	}
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, 0)
+}
@@ -0,0 +1,282 @@
+package contentmanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// maxAssetSize is the largest file served from the posts repository.
+const maxAssetSize = 20 << 20
+
+// assetTypes are the file extensions served from the posts repository.
+var assetTypes = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".avif": true, ".svg": true,
+	".pdf": true, ".zip": true, ".txt": true, ".csv": true, ".json": true, ".yaml": true, ".yml": true,
+	".mp4": true, ".webm": true,
+}
+
+// Asset is an image or other file a post references from the posts
+// repository, or the thumbnail of one of its shortcodes.
+type Asset struct {
+	Path        string
+	ContentType string
+	Data        []byte
+	Hash        string
+	etag        string
+
+	// source is the Hash of the image a resized variant was made from, and
+	// cacheName the name of its file in the image cache.
+	source    string
+	cacheName string
+}
+
+// assetsKey holds the assets collected during a refresh in the
+// parser.Context.
+var assetsKey = parser.NewContextKey()
+
+// assetKey identifies an asset by the post it is served under and its path
+// relative to that post.
+func assetKey(slug, name string) string {
+	return slug + "/" + name
+}
+
+// GetAsset returns the file a post references as name, relative to the
+// post's markdown file.
+func (cm *ContentManager) GetAsset(slug, name string) (*Asset, bool) {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	asset, ok := cm.assets[assetKey(slug, name)]
+	return asset, ok
+}
+
+// fetchAsset downloads a file from the posts repository. When previous is a
+// copy fetched in an earlier refresh, GitHub is asked only for changes.
+func (cm *ContentManager) fetchAsset(repoPath string, previous *Asset) (*Asset, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", cm.repoOwner, cm.repoName, (&url.URL{Path: repoPath}).EscapedPath())
+
+	req, err := cm.newRequest(u)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw+json")
+	if previous != nil && previous.etag != "" {
+		req.Header.Set("If-None-Match", previous.etag)
+	}
+
+	resp, err := cm.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return previous, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAssetSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxAssetSize)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(repoPath))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return &Asset{
+		Path:        repoPath,
+		ContentType: contentType,
+		Data:        data,
+		Hash:        hex.EncodeToString(sum[:6]),
+		etag:        resp.Header.Get("ETag"),
+	}, nil
+}
+
+// assetTransformer fetches images and files that posts reference by paths
+// relative to their markdown file, and points the references at
+// /posts/:slug/assets/... with the content hash as a cache buster. Images
+// load lazily and, when ResponsiveImages is configured, list resized
+// variants in their srcset. Shortcode thumbnails are fetched and served the
+// same way.
+type assetTransformer struct {
+	cm *ContentManager
+}
+
+func (t *assetTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	post, ok := PostFromContext(pc)
+	collected, _ := pc.Get(assetsKey).(*assetCollector)
+	if !ok || post.Slug == "" || collected == nil {
+		return
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Image:
+			n.SetAttributeString("loading", []byte("lazy"))
+			n.SetAttributeString("decoding", []byte("async"))
+			if name, asset, ok := t.resolve(post, string(n.Destination), collected); ok {
+				n.Destination = []byte(AssetURL(post.Slug, name, asset))
+				t.responsiveImage(n, post, name, asset, collected)
+			}
+		case *ast.Link:
+			if name, asset, ok := t.resolve(post, string(n.Destination), collected); ok {
+				n.Destination = []byte(AssetURL(post.Slug, name, asset))
+			}
+		case *ShortcodeNode:
+			t.thumbnail(post, n, collected)
+		case *ShortcodeBlock:
+			t.thumbnail(post, n.Shortcode, collected)
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+// resolve fetches the asset a post references as destination and returns
+// its name relative to the post.
+func (t *assetTransformer) resolve(post Post, destination string, collected *assetCollector) (string, *Asset, bool) {
+	name, ok := relativeAssetPath(destination)
+	if !ok {
+		return "", nil, false
+	}
+
+	repoPath := path.Join(path.Dir(post.Path), name)
+
+	key := assetKey(post.Slug, name)
+	asset, ok := collected.assets[key]
+	if !ok {
+		var err error
+		if asset, err = t.cm.fetchAsset(repoPath, collected.previous[key]); err != nil {
+			log.Printf("Failed to fetch asset %s for %s: %v", repoPath, post.Path, err)
+			return "", nil, false
+		}
+		collected.assets[key] = asset
+	}
+
+	return name, asset, true
+}
+
+// thumbnail fetches the preview image registered for a shortcode and passes
+// the URL it is served from to the shortcode. Thumbnails are named after
+// their source URL, so one fetched in an earlier refresh is reused.
+func (t *assetTransformer) thumbnail(post Post, n *ShortcodeNode, collected *assetCollector) {
+	source, ok := t.cm.shortcodes.Thumbnail(n.Name, n.Args)
+	if !ok {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	name := "thumbnails/" + hex.EncodeToString(sum[:6])
+	if u, err := url.Parse(source); err == nil && assetTypes[strings.ToLower(path.Ext(u.Path))] {
+		name += strings.ToLower(path.Ext(u.Path))
+	}
+
+	key := assetKey(post.Slug, name)
+	asset, ok := collected.assets[key]
+	if !ok {
+		if asset = collected.previous[key]; asset == nil {
+			var err error
+			if asset, err = t.cm.fetchThumbnail(source); err != nil {
+				log.Printf("Failed to fetch thumbnail %s for %s: %v", source, post.Path, err)
+				return
+			}
+		}
+		collected.assets[key] = asset
+	}
+
+	n.Args.Thumbnail = AssetURL(post.Slug, name, asset)
+}
+
+// fetchThumbnail downloads a shortcode's preview image from the site that
+// hosts it.
+func (cm *ContentManager) fetchThumbnail(source string) (*Asset, error) {
+	resp, err := cm.client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAssetSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxAssetSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("not an image: %s", contentType)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return &Asset{
+		Path:        source,
+		ContentType: contentType,
+		Data:        data,
+		Hash:        hex.EncodeToString(sum[:6]),
+	}, nil
+}
+
+// relativeAssetPath returns the cleaned path of a link to a file beside or
+// below the post in the posts repository, or false for URLs, site paths,
+// anchors, parent directories and unsupported files.
+func relativeAssetPath(destination string) (string, bool) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	if !assetTypes[strings.ToLower(path.Ext(u.Path))] {
+		return "", false
+	}
+
+	name := path.Clean(u.Path)
+	if strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
+
+// AssetURL is the URL an asset is served from. The hash changes with the
+// content, so responses can be cached indefinitely.
+func AssetURL(slug, name string, asset *Asset) string {
+	return "/posts/" + url.PathEscape(slug) + "/assets/" + (&url.URL{Path: name}).EscapedPath() + "?v=" + asset.Hash
+}
+
+// assetCollector gathers the assets referenced during a refresh.
+type assetCollector struct {
+	assets   map[string]*Asset
+	previous map[string]*Asset
+	images   imageOptions
+}
@@ -0,0 +1,237 @@
+package contentmanager
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/stratocraft/stratocraft.dev/internal/views/callouts"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// calloutAliases maps the kinds accepted in posts onto the styles rendered
+// by callouts.Callout.
+var calloutAliases = map[string]string{
+	"note":      "note",
+	"info":      "note",
+	"tip":       "tip",
+	"hint":      "tip",
+	"important": "important",
+	"warning":   "warning",
+	"caution":   "caution",
+	"danger":    "caution",
+}
+
+// Callout is a note, tip or warning box wrapping block content.
+type Callout struct {
+	ast.BaseBlock
+	CalloutKind string
+	Title       string
+	fence       int
+}
+
+// KindCallout is the NodeKind of Callout nodes.
+var KindCallout = ast.NewNodeKind("Callout")
+
+// Kind implements ast.Node.
+func (n *Callout) Kind() ast.NodeKind {
+	return KindCallout
+}
+
+// Dump implements ast.Node.
+func (n *Callout) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Kind":  n.CalloutKind,
+		"Title": n.Title,
+	}, nil)
+}
+
+// calloutExtension adds GitHub-style "> [!NOTE]" alerts and ":::tip"
+// containers.
+type calloutExtension struct{}
+
+func (e *calloutExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&calloutBlockParser{}, 90)),
+		parser.WithASTTransformers(util.Prioritized(&alertTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&calloutRenderer{markdown: m}, 500)),
+	)
+}
+
+var calloutOpenPattern = regexp.MustCompile(`^(:{3,})\s*([A-Za-z]+)\s*(.*?)\s*$`)
+
+// calloutBlockParser parses containers fenced by ::: lines. Use a longer
+// fence on the outer container to nest them.
+//
+//	:::warning Optional title
+//	Content
+//	:::
+type calloutBlockParser struct{}
+
+func (b *calloutBlockParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (b *calloutBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	match := calloutOpenPattern.FindSubmatch(bytes.TrimRight(line, "\r\n"))
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+
+	kind, ok := calloutAliases[strings.ToLower(string(match[2]))]
+	if !ok {
+		return nil, parser.NoChildren
+	}
+
+	reader.Advance(segment.Len() - 1)
+
+	return &Callout{
+		CalloutKind: kind,
+		Title:       string(match[3]),
+		fence:       len(match[1]),
+	}, parser.HasChildren
+}
+
+func (b *calloutBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	trimmed := bytes.TrimSpace(line)
+
+	if len(trimmed) >= node.(*Callout).fence && len(bytes.Trim(trimmed, ":")) == 0 && !inFencedCode(node, pc) {
+		// Leave the newline so the closing line reads as blank to the parser
+		newline := 0
+		if line[len(line)-1] == '\n' {
+			newline = 1
+		}
+		reader.Advance(segment.Len() - newline)
+		return parser.Close
+	}
+
+	return parser.Continue | parser.HasChildren
+}
+
+// inFencedCode reports whether a fenced code block inside node is still
+// open, so a line of colons is code rather than the callout's closing fence.
+func inFencedCode(node ast.Node, pc parser.Context) bool {
+	inside := false
+	for _, block := range pc.OpenedBlocks() {
+		if block.Node == node {
+			inside = true
+		} else if _, ok := block.Node.(*ast.FencedCodeBlock); ok && inside {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *calloutBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *calloutBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *calloutBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+var alertPattern = regexp.MustCompile(`^\[!([A-Za-z]+)\]\s*$`)
+
+// alertTransformer turns blockquotes that start with a [!KIND] marker line
+// into callouts, matching GitHub's alert syntax.
+type alertTransformer struct{}
+
+func (t *alertTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var quotes []*ast.Blockquote
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if quote, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, quote)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, quote := range quotes {
+		para, ok := quote.FirstChild().(*ast.Paragraph)
+		if !ok || para.Lines().Len() == 0 {
+			continue
+		}
+
+		first := para.Lines().At(0)
+		match := alertPattern.FindSubmatch(bytes.TrimSpace(first.Value(source)))
+		if match == nil {
+			continue
+		}
+
+		kind, ok := calloutAliases[strings.ToLower(string(match[1]))]
+		if !ok {
+			continue
+		}
+
+		// Drop the marker line, leaving any text that followed it
+		for child := para.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			textNode, isText := child.(*ast.Text)
+			if isText && textNode.Segment.Start >= first.Stop {
+				break
+			}
+			para.RemoveChild(para, child)
+			if isText && (textNode.SoftLineBreak() || textNode.HardLineBreak()) {
+				break
+			}
+			child = next
+		}
+		if para.ChildCount() == 0 {
+			quote.RemoveChild(quote, para)
+		}
+
+		callout := &Callout{CalloutKind: kind}
+		for child := quote.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			callout.AppendChild(callout, child)
+			child = next
+		}
+		quote.Parent().ReplaceChild(quote.Parent(), quote, callout)
+	}
+}
+
+// calloutRenderer renders the callout's content first and passes it to the
+// callouts.Callout templ component as its children.
+type calloutRenderer struct {
+	markdown goldmark.Markdown
+}
+
+func (r *calloutRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindCallout, r.renderCallout)
+}
+
+func (r *calloutRenderer) renderCallout(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*Callout)
+
+	var body bytes.Buffer
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		if err := r.markdown.Renderer().Render(&body, source, child); err != nil {
+			return ast.WalkStop, err
+		}
+	}
+
+	ctx := templ.WithChildren(context.Background(), templ.Raw(body.String()))
+	if err := callouts.Callout(n.CalloutKind, n.Title).Render(ctx, w); err != nil {
+		return ast.WalkStop, err
+	}
+
+	return ast.WalkSkipChildren, nil
+}
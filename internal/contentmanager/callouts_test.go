@@ -0,0 +1,61 @@
+package contentmanager
+
+import (
+	"strings"
+	"testing"
+)
+
+// renderMarkdown renders body with the default pipeline.
+func renderMarkdown(t *testing.T, body string) string {
+	t.Helper()
+
+	post := Post{Path: "test.md", Slug: "test"}
+	if err := New("owner", "repo").newPipeline().render(&post, []byte(body)); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	return post.Content
+}
+
+func TestCalloutFenceInsideCode(t *testing.T) {
+	html := renderMarkdown(t, ":::note\n```\n:::\n```\nafter\n:::\n\noutside\n")
+
+	code, rest, ok := strings.Cut(html, "</pre>")
+	if !ok || strings.Count(html, "<pre") != 1 {
+		t.Fatalf("expected one code block, got:\n%s", html)
+	}
+	if !strings.Contains(code, ":::") {
+		t.Errorf("expected the colons inside the code block, got:\n%s", html)
+	}
+
+	callout, outside, ok := strings.Cut(rest, "</aside>")
+	if !ok || !strings.Contains(callout, "<p>after</p>") {
+		t.Errorf("expected the text after the code block in the callout, got:\n%s", html)
+	}
+	if strings.TrimSpace(outside) != "<p>outside</p>" {
+		t.Errorf("expected the callout to close on its own fence, got:\n%s", html)
+	}
+}
+
+func TestCalloutCloses(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		content string
+	}{
+		{"trailing newline", ":::tip Title\ninside\n:::\n\noutside\n", "<p>inside</p>"},
+		{"no trailing newline", ":::tip Title\ninside\n:::", "<p>inside</p>"},
+		{"nested", "::::warning\n:::note\ninner\n:::\nouter\n::::\n", "<p>inner</p>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			html := renderMarkdown(t, test.body)
+			if strings.Contains(html, ":::") {
+				t.Errorf("fence left in the output:\n%s", html)
+			}
+			if !strings.Contains(html, test.content) {
+				t.Errorf("expected %s, got:\n%s", test.content, html)
+			}
+		})
+	}
+}
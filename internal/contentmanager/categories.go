@@ -0,0 +1,181 @@
+package contentmanager
+
+import (
+	"sort"
+	"strings"
+)
+
+// Category is a path in the category hierarchy, from the top level down,
+// such as Cloud > Azure > AKS.
+type Category []string
+
+// parseCategory reads a category from frontmatter, written as
+// "Cloud/Azure/AKS" or "Cloud > Azure > AKS".
+func parseCategory(s string) Category {
+	var category Category
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '/' || r == '>' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			category = append(category, name)
+		}
+	}
+	return category
+}
+
+// String returns the category as it is written in frontmatter.
+func (c Category) String() string {
+	return strings.Join(c, " > ")
+}
+
+// Contains reports whether other is c or one of its subcategories, ignoring
+// case.
+func (c Category) Contains(other Category) bool {
+	if len(other) < len(c) {
+		return false
+	}
+	for i := range c {
+		if !strings.EqualFold(c[i], other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCategories reads the categories in a post's frontmatter, dropping
+// blanks and duplicates.
+func parseCategories(values []string) []Category {
+	var categories []Category
+	for _, value := range values {
+		category := parseCategory(value)
+		if len(category) == 0 {
+			continue
+		}
+
+		duplicate := false
+		for _, existing := range categories {
+			if len(existing) == len(category) && existing.Contains(category) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// CategoryNode is a category with the number of published posts in it or
+// its subcategories.
+type CategoryNode struct {
+	Name     string
+	Path     Category
+	Count    int
+	Children []CategoryNode
+}
+
+// categoryTree is a CategoryNode while the tree is being built.
+type categoryTree struct {
+	name     string
+	path     Category
+	slugs    map[string]bool
+	children map[string]*categoryTree
+}
+
+func (t *categoryTree) child(name string) *categoryTree {
+	key := strings.ToLower(name)
+	if t.children[key] == nil {
+		path := append(append(Category(nil), t.path...), name)
+		t.children[key] = &categoryTree{
+			name:     name,
+			path:     path,
+			slugs:    make(map[string]bool),
+			children: make(map[string]*categoryTree),
+		}
+	}
+	return t.children[key]
+}
+
+// node converts the tree, ordering children by name.
+func (t *categoryTree) node() CategoryNode {
+	node := CategoryNode{Name: t.name, Path: t.path, Count: len(t.slugs)}
+	for _, child := range t.children {
+		node.Children = append(node.Children, child.node())
+	}
+	sort.Slice(node.Children, func(i, j int) bool {
+		return strings.ToLower(node.Children[i].Name) < strings.ToLower(node.Children[j].Name)
+	})
+	return node
+}
+
+// unifyCategorySpellings gives each category one spelling across all posts,
+// so Cloud > Azure and cloud > azure are the same category.
+func unifyCategorySpellings(posts map[string]Post) {
+	uses := make(spellings)
+	for _, post := range posts {
+		for _, category := range post.Categories {
+			for i, name := range category {
+				uses.add(Category(category[:i+1]).String(), name)
+			}
+		}
+	}
+
+	for _, post := range posts {
+		for _, category := range post.Categories {
+			for i := len(category) - 1; i >= 0; i-- {
+				category[i] = uses.preferred(Category(category[:i+1]).String())
+			}
+		}
+	}
+}
+
+// categoryRoot builds the category tree of the published posts. The caller
+// must hold the lock.
+func (cm *ContentManager) categoryRoot() *categoryTree {
+	root := &categoryTree{slugs: make(map[string]bool), children: make(map[string]*categoryTree)}
+
+	for _, post := range cm.posts {
+		for _, category := range post.Categories {
+			tree := root
+			for _, name := range category {
+				tree = tree.child(name)
+				tree.slugs[post.Slug] = true
+			}
+		}
+	}
+
+	return root
+}
+
+// GetCategories returns the top-level categories and their subcategories.
+func (cm *ContentManager) GetCategories() []CategoryNode {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	return cm.categoryRoot().node().Children
+}
+
+// GetCategory returns a category, matched ignoring case, with its
+// subcategories.
+func (cm *ContentManager) GetCategory(path Category) (CategoryNode, bool) {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	if len(path) == 0 {
+		return CategoryNode{}, false
+	}
+
+	tree := cm.categoryRoot()
+	for _, name := range path {
+		if tree = tree.children[strings.ToLower(name)]; tree == nil {
+			return CategoryNode{}, false
+		}
+	}
+
+	return tree.node(), true
+}
+
+// GetByCategory returns the posts in a category or any of its
+// subcategories, newest first.
+func (cm *ContentManager) GetByCategory(path Category) []Post {
+	return cm.Query().Category(path).Posts()
+}
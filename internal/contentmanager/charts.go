@@ -0,0 +1,146 @@
+package contentmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stratocraft/stratocraft.dev/internal/views/charts"
+	"github.com/yuin/goldmark/util"
+)
+
+// chartSpec is the JSON form of a chart block.
+type chartSpec struct {
+	Type   string   `json:"type"`
+	Title  string   `json:"title"`
+	X      string   `json:"x"`
+	Y      string   `json:"y"`
+	Labels []string `json:"labels"`
+	Series []struct {
+		Name   string    `json:"name"`
+		Values []float64 `json:"values"`
+	} `json:"series"`
+}
+
+// parseChart reads a ```chart block. It is either a JSON chartSpec or
+// "key: value" settings (type, title, x, y) followed by a --- line and CSV
+// whose first column holds the labels and each further column a series:
+//
+//	type: bar
+//	title: Requests per second
+//	y: req/s
+//	---
+//	router,echo,gin
+//	static,120000,115000
+func parseChart(code string) (charts.Chart, error) {
+	var chart charts.Chart
+
+	if strings.HasPrefix(strings.TrimSpace(code), "{") {
+		var spec chartSpec
+		if err := json.Unmarshal([]byte(code), &spec); err != nil {
+			return chart, fmt.Errorf("invalid chart JSON: %w", err)
+		}
+
+		chart = charts.Chart{Type: spec.Type, Title: spec.Title, XLabel: spec.X, YLabel: spec.Y, Labels: spec.Labels}
+		for _, s := range spec.Series {
+			chart.Series = append(chart.Series, charts.Series{Name: s.Name, Values: s.Values})
+		}
+	} else {
+		settings, data, found := strings.Cut(code, "\n---\n")
+		if !found {
+			settings, data = "", code
+		}
+
+		for _, line := range strings.Split(settings, "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "type":
+				chart.Type = strings.ToLower(value)
+			case "title":
+				chart.Title = value
+			case "x":
+				chart.XLabel = value
+			case "y":
+				chart.YLabel = value
+			default:
+				return chart, fmt.Errorf("unknown chart setting %q", key)
+			}
+		}
+
+		if err := parseChartCSV(&chart, data); err != nil {
+			return chart, err
+		}
+	}
+
+	if chart.Type == "" {
+		chart.Type = "bar"
+	}
+	if chart.Type != "bar" && chart.Type != "line" {
+		return chart, fmt.Errorf("unknown chart type %q", chart.Type)
+	}
+	if len(chart.Labels) == 0 || len(chart.Series) == 0 {
+		return chart, fmt.Errorf("chart has no data")
+	}
+
+	return chart, nil
+}
+
+func parseChartCSV(chart *charts.Chart, data string) error {
+	reader := csv.NewReader(strings.NewReader(strings.TrimSpace(data)))
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("invalid chart CSV: %w", err)
+	}
+	if len(rows) < 2 || len(rows[0]) < 2 {
+		return fmt.Errorf("chart CSV needs a header row and at least one data row")
+	}
+
+	if chart.XLabel == "" {
+		chart.XLabel = rows[0][0]
+	}
+	for _, name := range rows[0][1:] {
+		chart.Series = append(chart.Series, charts.Series{Name: name})
+	}
+
+	for _, row := range rows[1:] {
+		chart.Labels = append(chart.Labels, row[0])
+		for i := range chart.Series {
+			value, err := strconv.ParseFloat(strings.TrimSpace(row[i+1]), 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for %s", row[i+1], row[0])
+			}
+			chart.Series[i].Values = append(chart.Series[i].Values, value)
+		}
+	}
+
+	return nil
+}
+
+// writeChart renders a ```chart block as inline SVG.
+func writeChart(w util.BufWriter, code string) error {
+	chart, err := parseChart(code)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := charts.Render(chart).Render(context.Background(), &buf); err != nil {
+		return err
+	}
+
+	_, _ = w.Write(buf.Bytes())
+	_ = w.WriteByte('\n')
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package contentmanager
+
+import (
+	"strconv"
+	"strings"
+)
+
+// codeBlockOptions are the settings a fenced code block's info string can
+// carry, for example:
+//
+//	```go title="main.go" {3-5} linenos
+type codeBlockOptions struct {
+	Language    string
+	Title       string
+	LineNumbers bool
+	Start       int
+	Diff        bool
+	Highlight   [][2]int
+}
+
+// isHighlighted reports whether the 1-based line falls in a highlighted range.
+func (o codeBlockOptions) isHighlighted(line int) bool {
+	for _, r := range o.Highlight {
+		if line >= r[0] && line <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCodeInfo parses a fenced code block info string. The first bare word
+// is the language; the rest may be key=value or key="quoted value" pairs,
+// {1,3-5} line ranges to highlight and bare flags. Unknown attributes are
+// ignored so posts written for other renderers still display.
+func parseCodeInfo(info string) codeBlockOptions {
+	options := codeBlockOptions{Start: 1}
+
+	for i, field := range splitCodeInfo(info) {
+		key, value, hasValue := strings.Cut(field, "=")
+		value = strings.Trim(value, `"'`)
+
+		switch {
+		case strings.HasPrefix(field, "{") && strings.HasSuffix(field, "}"):
+			options.Highlight = append(options.Highlight, parseLineRanges(field[1:len(field)-1])...)
+		case hasValue && (key == "title" || key == "filename"):
+			options.Title = value
+		case hasValue && (key == "hl_lines" || key == "highlight"):
+			options.Highlight = append(options.Highlight, parseLineRanges(value)...)
+		case hasValue && (key == "start" || key == "linenostart"):
+			if start, err := strconv.Atoi(value); err == nil && start > 0 {
+				options.Start = start
+				options.LineNumbers = true
+			}
+		case hasValue && key == "linenos":
+			options.LineNumbers = value != "false"
+		case !hasValue && (field == "linenos" || field == "showLineNumbers"):
+			options.LineNumbers = true
+		case !hasValue && field == "diff":
+			options.Diff = true
+		case !hasValue && i == 0:
+			options.Language = field
+		}
+	}
+
+	return options
+}
+
+// splitCodeInfo splits an info string on spaces, keeping quoted values and
+// {} ranges together.
+func splitCodeInfo(info string) []string {
+	var fields []string
+	var field strings.Builder
+	var quote rune
+	inBraces := false
+
+	for _, r := range info {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '{':
+			inBraces = true
+		case r == '}':
+			inBraces = false
+		case r == ' ' || r == '\t':
+			if !inBraces {
+				if field.Len() > 0 {
+					fields = append(fields, field.String())
+					field.Reset()
+				}
+				continue
+			}
+		}
+		field.WriteRune(r)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+// parseLineRanges parses "1,3-5" into inclusive line ranges, skipping
+// anything malformed.
+func parseLineRanges(spec string) [][2]int {
+	var ranges [][2]int
+
+	for _, part := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(part, "-")
+
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		if end < start {
+			start, end = end, start
+		}
+
+		ranges = append(ranges, [2]int{start, end})
+	}
+
+	return ranges
+}
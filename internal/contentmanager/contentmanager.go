@@ -6,19 +6,40 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/util"
 )
 
 type ContentManager struct {
 	sync.RWMutex
-	posts       map[string]Post
-	client      *http.Client
-	repoOwner   string
-	repoName    string
-	githubToken string
+	posts            map[string]Post
+	history          map[string][]Revision
+	revisions        map[string]string
+	assets           map[string]*Asset
+	index            *postIndex
+	listed           time.Time
+	tags             *TagRegistry
+	shortcodes       *ShortcodeRegistry
+	extensions       []goldmark.Extender
+	extensionNames   map[string]bool
+	astTransformers  []util.PrioritizedValue
+	nodeRenderers    []util.PrioritizedValue
+	htmlTransformers []HTMLTransformer
+	sanitizePolicy   *bluemonday.Policy
+	trustedSources   []string
+	images           imageOptions
+	client           *http.Client
+	repoOwner        string
+	repoName         string
+	githubToken      string
 }
 
 func New(repoOwner, repoName string) *ContentManager {
@@ -27,13 +48,22 @@ func New(repoOwner, repoName string) *ContentManager {
 		log.Println("Warning: GITHUB_TOKEN environment variable not set. API requests will be rate limited.")
 	}
 
-	return &ContentManager{
-		posts:       make(map[string]Post),
-		client:      &http.Client{},
-		repoOwner:   repoOwner,
-		repoName:    repoName,
-		githubToken: githubToken,
+	cm := &ContentManager{
+		posts:          make(map[string]Post),
+		history:        make(map[string][]Revision),
+		revisions:      make(map[string]string),
+		assets:         make(map[string]*Asset),
+		extensionNames: make(map[string]bool),
+		shortcodes:     NewShortcodeRegistry(),
+		sanitizePolicy: DefaultSanitizePolicy(),
+		client:         &http.Client{},
+		repoOwner:      repoOwner,
+		repoName:       repoName,
+		githubToken:    githubToken,
 	}
+	cm.useDefaultPipeline()
+
+	return cm
 }
 
 //func (cm *ContentManager) listRepoContent(path string) ([]githubContent, error) {
@@ -74,23 +104,34 @@ func New(repoOwner, repoName string) *ContentManager {
 //	return contents, nil
 //}
 
-func (cm *ContentManager) listRepoContent(path string) ([]githubContent, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", cm.repoOwner, cm.repoName, path)
-
-	log.Printf("fetching content from: %s", url)
-
+// newRequest builds a GitHub API request with the standard headers and,
+// when available, the configured token.
+func (cm *ContentManager) newRequest(url string) (*http.Request, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	
+
 	// Add authentication if token is available
 	if cm.githubToken != "" {
 		req.Header.Set("Authorization", "token "+cm.githubToken)
 	}
 
+	return req, nil
+}
+
+func (cm *ContentManager) listRepoContent(path string) ([]githubContent, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", cm.repoOwner, cm.repoName, path)
+
+	log.Printf("fetching content from: %s", url)
+
+	req, err := cm.newRequest(url)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := cm.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -123,26 +164,32 @@ func (cm *ContentManager) listRepoContent(path string) ([]githubContent, error)
 }
 
 func (cm *ContentManager) fetchFileContent(path string) (string, error) {
+	return cm.fetchFileContentAt(path, "")
+}
+
+// fetchFileContentAt fetches a file as it was at ref, which may be a commit
+// SHA or branch name. An empty ref uses the default branch.
+func (cm *ContentManager) fetchFileContentAt(path, ref string) (string, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", cm.repoOwner, cm.repoName, path)
+	if ref != "" {
+		url += "?ref=" + neturl.QueryEscape(ref)
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := cm.newRequest(url)
 	if err != nil {
 		return "", err
 	}
 
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	
-	// Add authentication if token is available
-	if cm.githubToken != "" {
-		req.Header.Set("Authorization", "token "+cm.githubToken)
-	}
-
 	resp, err := cm.client.Do(req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
 	var result struct {
 		Content  string `json:"content"`
 		Encoding string `json:"encoding"`
@@ -164,6 +211,55 @@ func (cm *ContentManager) fetchFileContent(path string) (string, error) {
 	return result.Content, nil
 }
 
+// fetchCommits returns up to limit commits that touched path, newest first.
+func (cm *ContentManager) fetchCommits(path string, limit int) ([]githubCommit, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits?path=%s&per_page=%d",
+		cm.repoOwner, cm.repoName, neturl.QueryEscape(path), limit)
+
+	req, err := cm.newRequest(url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cm.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var commits []githubCommit
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// lastModified resolves when a post was last revised. A lastmod frontmatter
+// value wins, then the date of the latest commit touching the file, and
+// finally the publish date.
+func (cm *ContentManager) lastModified(path string, post Post) time.Time {
+	if !post.Updated.IsZero() {
+		return post.Updated
+	}
+
+	commits, err := cm.fetchCommits(path, 1)
+	if err != nil {
+		log.Printf("Failed to fetch commit history for %s: %v", path, err)
+		return post.Date
+	}
+
+	if len(commits) == 0 || commits[0].Commit.Committer.Date.Before(post.Date) {
+		return post.Date
+	}
+
+	return commits[0].Commit.Committer.Date
+}
+
 func matchesAllTerms(post Post, terms []string) bool {
 	searchText := strings.ToLower(strings.Join([]string{
 		post.Title,
@@ -181,6 +277,12 @@ func matchesAllTerms(post Post, terms []string) bool {
 	return true
 }
 
+// postSource is a markdown file fetched during a refresh.
+type postSource struct {
+	file    githubContent
+	content string
+}
+
 func (cm *ContentManager) RefreshContent() error {
 	// List files in content directory
 	files, err := cm.listRepoContent("")
@@ -189,6 +291,13 @@ func (cm *ContentManager) RefreshContent() error {
 	}
 
 	newPosts := make(map[string]Post)
+	p := cm.newPipeline()
+
+	tags, err := cm.loadTagRegistry(files)
+	if err != nil {
+		return err
+	}
+	p.tags = tags
 
 	// Files to ignore
 	ignoredFiles := map[string]bool{
@@ -199,7 +308,8 @@ func (cm *ContentManager) RefreshContent() error {
 
 	log.Printf("Found %d files in repository", len(files))
 
-	// Process each markdown file
+	// Fetch each markdown file
+	var sources []postSource
 	for _, file := range files {
 		// Skip if not a file or not a markdown file
 		if file.Type != "file" || !strings.HasSuffix(file.Name, ".md") {
@@ -221,13 +331,25 @@ func (cm *ContentManager) RefreshContent() error {
 			return fmt.Errorf("failed to fecth %s: %w", file.Name, err)
 		}
 
-		post, err := parseMarkdown(content)
+		// Record slugs up front so posts can link to ones parsed after them
+		if fm, _, err := parseFrontMatter([]byte(content)); err == nil && fm.Slug != "" && fm.Published {
+			p.slugs[file.Path] = fm.Slug
+		}
+
+		sources = append(sources, postSource{file: file, content: content})
+	}
+
+	// Parse each markdown file
+	for _, source := range sources {
+		file, content := source.file, source.content
+
+		post, err := parseMarkdown(content, file.Path, p)
 		if err != nil {
 			log.Printf("Failed to parse %s: %v", file.Name, err)
 			return fmt.Errorf("failed to parse %s: %w", file.Name, err)
 		}
 
-		log.Printf("Parsed post: Title='%s', Slug='%s', Published=%v, Tags=%v", 
+		log.Printf("Parsed post: Title='%s', Slug='%s', Published=%v, Tags=%v",
 			post.Title, post.Slug, post.Published, post.Tags)
 
 		// Check for empty slug
@@ -242,77 +364,82 @@ func (cm *ContentManager) RefreshContent() error {
 			continue
 		}
 
+		post.Updated = cm.lastModified(file.Path, post)
+
 		newPosts[post.Slug] = post
 	}
 
 	log.Printf("Successfully processed %d posts", len(newPosts))
 
+	unifyTagSpellings(newPosts)
+	unifyCategorySpellings(newPosts)
+	unifySeriesSpellings(newPosts)
+	index := buildIndex(newPosts)
+
+	// Keep only the assets of published posts
+	newAssets := make(map[string]*Asset)
+	for key, asset := range p.assets.assets {
+		slug, _, _ := strings.Cut(key, "/")
+		if _, ok := newPosts[slug]; ok {
+			newAssets[key] = asset
+		}
+	}
+
 	// Update posts atomically
 	cm.Lock()
+	cm.index = index
+	cm.assets = newAssets
+	cm.tags = tags
+	cm.history = make(map[string][]Revision)
+	if cm.listed.IsZero() || !sameSlugs(cm.posts, newPosts) {
+		cm.listed = time.Now()
+	}
 	cm.posts = newPosts
 	cm.Unlock()
 
+	p.assets.images.prune(newAssets)
+
 	return nil
 }
 
-func (cm *ContentManager) GetAll() []Post {
+// Listed returns when a refresh last published, unpublished or deleted a
+// post, changing which posts the site lists.
+func (cm *ContentManager) Listed() time.Time {
 	cm.RLock()
 	defer cm.RUnlock()
 
-	posts := make([]Post, 0, len(cm.posts))
-	for _, post := range cm.posts {
-		posts = append(posts, post)
-	}
-
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].Date.After(posts[j].Date)
-	})
-
-	return posts
+	return cm.listed
 }
 
-func (cm *ContentManager) GetByTag(tag string) []Post {
-	cm.RLock()
-	defer cm.RUnlock()
-
-	var tagged []Post
-
-	for _, post := range cm.posts {
-		for _, t := range post.Tags {
-			if t == tag {
-				tagged = append(tagged, post)
-				break
-			}
+// sameSlugs reports whether two sets of posts hold the same slugs.
+func sameSlugs(a, b map[string]Post) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for slug := range a {
+		if _, ok := b[slug]; !ok {
+			return false
 		}
 	}
+	return true
+}
 
-	sort.Slice(tagged, func(i, j int) bool {
-		return tagged[i].Date.After(tagged[j].Date)
-	})
+func (cm *ContentManager) GetAll() []Post {
+	return cm.Query().Posts()
+}
 
-	return tagged
+// GetByTag returns the posts with tag or one of its aliases, ignoring case,
+// newest first
+func (cm *ContentManager) GetByTag(tag string) []Post {
+	return cm.Query().Tags(tag).Posts()
 }
 
 func (cm *ContentManager) GetRecent(n int) []Post {
-	posts := cm.GetAll()
-	if len(posts) < n {
-		return posts
-	}
-
-	return posts[:n]
+	return cm.Query().Limit(n).Posts()
 }
 
 func (cm *ContentManager) GetOldest(n int) []Post {
-	posts := cm.GetAll()
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].Date.Before(posts[j].Date)
-	})
-
-	if len(posts) < n {
-		return posts
-	}
-
-	return posts[:n]
+	return cm.Query().SortBy(SortOldest).Limit(n).Posts()
 }
 
 func (cm *ContentManager) Search(query string) []Post {
@@ -0,0 +1,57 @@
+package contentmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	emoji "github.com/yuin/goldmark-emoji"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdownExtensions are the optional markdown features a site can enable
+// with EnableMarkdownExtensions.
+var markdownExtensions = map[string]goldmark.Extender{
+	"footnotes": extension.NewFootnote(
+		extension.WithFootnoteBacklinkTitle("Back to text"),
+	),
+	"definitionlists": extension.DefinitionList,
+	"typographer":     extension.Typographer,
+	"emoji":           emoji.Emoji,
+	"abbreviations":   &abbreviationExtension{},
+	"math":            &mathExtension{},
+}
+
+// EnableMarkdownExtensions turns on the named optional markdown features for
+// posts parsed from now on: footnotes, definitionlists, typographer, emoji
+// abbreviations and math. Names are case-insensitive and may be comma separated.
+// Enabling an extension again has no effect.
+func (cm *ContentManager) EnableMarkdownExtensions(names ...string) error {
+	var enabled []string
+	for _, name := range names {
+		for _, field := range strings.Split(name, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if field == "" {
+				continue
+			}
+
+			if _, ok := markdownExtensions[field]; !ok {
+				return fmt.Errorf("unknown markdown extension %q", field)
+			}
+			enabled = append(enabled, field)
+		}
+	}
+
+	cm.Lock()
+	defer cm.Unlock()
+
+	for _, name := range enabled {
+		if cm.extensionNames[name] {
+			continue
+		}
+		cm.extensionNames[name] = true
+		cm.extensions = append(cm.extensions, markdownExtensions[name])
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package contentmanager
+
+import "testing"
+
+func TestEnableMarkdownExtensions(t *testing.T) {
+	cm := New("owner", "repo")
+	before := len(cm.extensions)
+
+	if err := cm.EnableMarkdownExtensions("math, Emoji", "math"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.EnableMarkdownExtensions("emoji,footnotes"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(cm.extensions) - before; got != 3 {
+		t.Errorf("enabled %d extensions, want 3", got)
+	}
+
+	if err := cm.EnableMarkdownExtensions("footnotes", "unknown"); err == nil {
+		t.Error("expected an error for an unknown extension")
+	}
+}
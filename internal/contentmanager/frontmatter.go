@@ -2,13 +2,22 @@ package contentmanager
 
 import "time"
 
+// FrontMatter is decoded by viper, which matches keys with the mapstructure
+// tags rather than the yaml ones.
 type FrontMatter struct {
-	ID        string    `yaml:"ID"`
-	Date      time.Time `yaml:"date"`
-	Title     string    `yaml:"title"`
-	Author    string    `yaml:"author"`
-	Summary   string    `yaml:"summary"`
-	Slug      string    `yaml:"slug"`
-	Tags      []string  `yaml:"tags"`
-	Published bool      `yaml:"published"`
+	ID          string    `yaml:"ID" mapstructure:"id"`
+	Date        time.Time `yaml:"date" mapstructure:"date"`
+	LastMod     time.Time `yaml:"lastmod" mapstructure:"lastmod"`
+	Title       string    `yaml:"title" mapstructure:"title"`
+	Author      string    `yaml:"author" mapstructure:"author"`
+	Summary     string    `yaml:"summary" mapstructure:"summary"`
+	Slug        string    `yaml:"slug" mapstructure:"slug"`
+	Tags        []string  `yaml:"tags" mapstructure:"tags"`
+	Categories  []string  `yaml:"categories" mapstructure:"categories"`
+	Section     string    `yaml:"section" mapstructure:"section"`
+	Series      string    `yaml:"series" mapstructure:"series"`
+	SeriesOrder int       `yaml:"series_order" mapstructure:"series_order"`
+	Related     []string  `yaml:"related" mapstructure:"related"`
+	Published   bool      `yaml:"published" mapstructure:"published"`
+	RawHTML     bool      `yaml:"rawhtml" mapstructure:"rawhtml"`
 }
@@ -1,8 +1,24 @@
 package contentmanager
 
+import "time"
+
 type githubContent struct {
 	Type string `json:"type"`
 	Name string `json:"name"`
 	Path string `json:"path"`
 	Size int    `json:"size"`
 }
+
+type githubCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
@@ -0,0 +1,140 @@
+package contentmanager
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// tocMinHeadings is how many headings a post needs before the table of
+// contents is worth showing.
+const tocMinHeadings = 3
+
+// TOCEntry is a heading in a post's table of contents.
+type TOCEntry struct {
+	ID       string
+	Title    string
+	Level    int
+	Children []TOCEntry
+}
+
+// buildTOC collects the headings in a document into a tree, nesting each
+// heading under the closest preceding heading of a higher level.
+func buildTOC(doc ast.Node, source []byte) []TOCEntry {
+	var flat []TOCEntry
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		id, ok := heading.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+
+		flat = append(flat, TOCEntry{
+			ID:    string(id.([]byte)),
+			Title: nodeText(heading, source),
+			Level: heading.Level,
+		})
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	toc, _ := nestTOC(flat, 0)
+
+	// A single top-level heading is the post's own title, so list its
+	// sections instead
+	if len(toc) == 1 && len(toc[0].Children) > 0 {
+		return toc[0].Children
+	}
+
+	return toc
+}
+
+// nestTOC consumes entries deeper than level and returns them as a tree
+// along with how many entries were used.
+func nestTOC(flat []TOCEntry, level int) ([]TOCEntry, int) {
+	var entries []TOCEntry
+
+	i := 0
+	for i < len(flat) && flat[i].Level > level {
+		entry := flat[i]
+		children, used := nestTOC(flat[i+1:], entry.Level)
+		entry.Children = children
+		entries = append(entries, entry)
+		i += used + 1
+	}
+
+	return entries, i
+}
+
+// nodeText returns the plain text content of a node, ignoring formatting.
+func nodeText(n ast.Node, source []byte) string {
+	var text strings.Builder
+
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch t := child.(type) {
+		case *ast.Text:
+			text.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				text.WriteByte(' ')
+			}
+		case *ast.String:
+			text.Write(t.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(text.String())
+}
+
+// countTOC returns the number of headings in a table of contents.
+func countTOC(entries []TOCEntry) int {
+	count := len(entries)
+	for _, entry := range entries {
+		count += countTOC(entry.Children)
+	}
+	return count
+}
+
+// headingRenderer renders headings with a permalink anchor after the text.
+type headingRenderer struct{}
+
+func (r *headingRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeading, r.renderHeading)
+}
+
+func (r *headingRenderer) renderHeading(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		_, _ = w.WriteString("<h")
+		_ = w.WriteByte("0123456"[n.Level])
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, node, html.HeadingAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+		return ast.WalkContinue, nil
+	}
+
+	if id, ok := n.AttributeString("id"); ok {
+		_, _ = w.WriteString(`<a class="heading-anchor" href="#`)
+		_, _ = w.Write(util.EscapeHTML(id.([]byte)))
+		_, _ = w.WriteString(`" aria-label="Link to this section">#</a>`)
+	}
+	_, _ = w.WriteString("</h")
+	_ = w.WriteByte("0123456"[n.Level])
+	_, _ = w.WriteString(">\n")
+
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,24 @@
+package contentmanager
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Page templates give their own elements IDs with an underscore, which
+// relies on heading IDs never containing one.
+func TestHeadingIDs(t *testing.T) {
+	html := renderMarkdown(t, "## Related Posts\n\n## Related_Posts\n\n## post_related\n")
+
+	for _, want := range []string{`id="related-posts"`, `id="related-posts-1"`, `id="post-related"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in:\n%s", want, html)
+		}
+	}
+	for _, id := range regexp.MustCompile(`id="([^"]*)"`).FindAllStringSubmatch(html, -1) {
+		if strings.Contains(id[1], "_") {
+			t.Errorf("heading ID %q contains an underscore", id[1])
+		}
+	}
+}
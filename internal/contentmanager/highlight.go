@@ -0,0 +1,209 @@
+package contentmanager
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// codeBlockRenderer highlights fenced and indented code blocks at parse time
+// so posts don't need client-side highlighting.
+type codeBlockRenderer struct{}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+}
+
+func (r *codeBlockRenderer) renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	options := codeBlockOptions{Start: 1}
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok && fenced.Info != nil {
+		options = parseCodeInfo(string(fenced.Info.Segment.Value(source)))
+	}
+
+	var code bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+
+	if options.Language == "chart" {
+		err := writeChart(w, code.String())
+		if err == nil {
+			return ast.WalkSkipChildren, nil
+		}
+		// Show the data as written so the post still makes sense
+		log.Printf("Failed to render chart: %v", err)
+	}
+
+	if err := writeCodeBlock(w, code.String(), options); err != nil {
+		return ast.WalkStop, err
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeCodeBlock writes code as a captioned <figure> holding a
+// <pre class="chroma"> block with token classes and a copy button. Unknown
+// languages are rendered as plain text.
+func writeCodeBlock(w util.BufWriter, code string, options codeBlockOptions) error {
+	var markers []byte
+	if options.Diff {
+		code, markers = stripDiffMarkers(code)
+	}
+
+	lexer := lexers.Get(options.Language)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return err
+	}
+	lines := chroma.SplitTokensIntoLines(iterator.Tokens())
+
+	_, _ = w.WriteString(`<figure class="code-block">`)
+	_, _ = w.WriteString(`<figcaption class="code-block-header">`)
+	switch {
+	case options.Title != "":
+		fmt.Fprintf(w, `<span class="code-block-title">%s</span>`, util.EscapeHTML([]byte(options.Title)))
+	case options.Language != "":
+		fmt.Fprintf(w, `<span class="code-block-language">%s</span>`, util.EscapeHTML([]byte(options.Language)))
+	default:
+		_, _ = w.WriteString(`<span></span>`)
+	}
+	_, _ = w.WriteString(`<button type="button" class="code-copy" data-copy-code aria-label="Copy code to clipboard">Copy</button>`)
+	_, _ = w.WriteString(`</figcaption>`)
+
+	_, _ = w.WriteString(`<pre class="chroma`)
+	if options.Diff {
+		_, _ = w.WriteString(` diff`)
+	}
+	_, _ = w.WriteString(`"><code`)
+	if options.Language != "" {
+		fmt.Fprintf(w, ` class="language-%s"`, util.EscapeHTML([]byte(options.Language)))
+	}
+	_ = w.WriteByte('>')
+
+	for i, tokens := range lines {
+		number := options.Start + i
+
+		_, _ = w.WriteString(`<span class="line`)
+		if options.isHighlighted(i + 1) {
+			_, _ = w.WriteString(` hl`)
+		}
+		if i < len(markers) {
+			switch markers[i] {
+			case '+':
+				_, _ = w.WriteString(` diff-add`)
+			case '-':
+				_, _ = w.WriteString(` diff-del`)
+			}
+		}
+		_, _ = w.WriteString(`">`)
+
+		if options.LineNumbers {
+			fmt.Fprintf(w, `<span class="ln">%*d</span>`, len(strconv.Itoa(options.Start+len(lines)-1)), number)
+		}
+
+		_, _ = w.WriteString(`<span class="cl">`)
+		for _, token := range tokens {
+			value := util.EscapeHTML([]byte(token.Value))
+			if class := tokenClass(token.Type); class != "" {
+				fmt.Fprintf(w, `<span class="%s">%s</span>`, class, value)
+			} else {
+				_, _ = w.Write(value)
+			}
+		}
+		_, _ = w.WriteString(`</span></span>`)
+	}
+
+	_, _ = w.WriteString(`</code></pre></figure>`)
+	_ = w.WriteByte('\n')
+
+	return nil
+}
+
+// tokenClass returns the chroma CSS class for a token type, falling back to
+// its parent category as the chroma HTML formatter does.
+func tokenClass(t chroma.TokenType) string {
+	for t != 0 {
+		if class, ok := chroma.StandardTypes[t]; ok {
+			return class
+		}
+		t = t.Parent()
+	}
+	return chroma.StandardTypes[t]
+}
+
+// stripDiffMarkers removes a leading + or - from each line of code and
+// returns the markers so the lines can be styled as added or removed.
+func stripDiffMarkers(code string) (string, []byte) {
+	lines := strings.SplitAfter(code, "\n")
+	markers := make([]byte, len(lines))
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			markers[i] = line[0]
+			lines[i] = line[1:]
+		}
+	}
+
+	return strings.Join(lines, ""), markers
+}
+
+// SyntaxCSS generates the stylesheet for highlighted code blocks. The light
+// style applies by default and the dark style when the page has the .dark
+// class, matching how the rest of the site switches themes.
+func SyntaxCSS(lightStyle, darkStyle string) ([]byte, error) {
+	light, err := syntaxStyle(lightStyle)
+	if err != nil {
+		return nil, err
+	}
+	dark, err := syntaxStyle(darkStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+
+	var lightCSS, darkCSS bytes.Buffer
+	if err := formatter.WriteCSS(&lightCSS, light); err != nil {
+		return nil, err
+	}
+	if err := formatter.WriteCSS(&darkCSS, dark); err != nil {
+		return nil, err
+	}
+
+	// Every rule WriteCSS emits starts with a comment followed by its selector
+	scoped := strings.ReplaceAll(darkCSS.String(), "*/ .", "*/ .dark .")
+
+	return append(lightCSS.Bytes(), scoped...), nil
+}
+
+// syntaxStyle looks up a chroma style by name. Unlike styles.Get, it doesn't
+// fall back to a default style, so a misspelt theme is reported.
+func syntaxStyle(name string) (*chroma.Style, error) {
+	style, ok := styles.Registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown syntax highlighting style %q", name)
+	}
+	return style, nil
+}
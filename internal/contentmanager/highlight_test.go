@@ -0,0 +1,32 @@
+package contentmanager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyntaxCSS(t *testing.T) {
+	css, err := SyntaxCSS("github", "github-dark")
+	if err != nil {
+		t.Fatalf("SyntaxCSS failed for known styles: %v", err)
+	}
+	if !strings.Contains(string(css), ".dark .chroma") {
+		t.Errorf("dark style isn't scoped to .dark:\n%s", css)
+	}
+
+	tests := []struct {
+		name        string
+		light, dark string
+	}{
+		{"unknown light", "nope", "github-dark"},
+		{"unknown dark", "github", "nope"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := SyntaxCSS(test.light, test.dark); err == nil {
+				t.Error("expected an error for an unknown style")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package contentmanager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// historyLimit caps how many commits are listed on a post's history page.
+const historyLimit = 50
+
+// Revision is a single commit that touched a post's source file.
+type Revision struct {
+	SHA     string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// ShortSHA returns the abbreviated commit hash used for display.
+func (r Revision) ShortSHA() string {
+	if len(r.SHA) > 7 {
+		return r.SHA[:7]
+	}
+	return r.SHA
+}
+
+// Subject returns the first line of the commit message.
+func (r Revision) Subject() string {
+	subject, _, _ := strings.Cut(r.Message, "\n")
+	return subject
+}
+
+// DiffOp describes how a DiffSegment changed between two revisions.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffSegment is a run of text that was kept, added or removed.
+type DiffSegment struct {
+	Op   DiffOp
+	Text string
+}
+
+// GetHistory returns the commits that touched the post, newest first. The
+// list is fetched from GitHub on first use and cached until the next refresh.
+func (cm *ContentManager) GetHistory(slug string) ([]Revision, error) {
+	cm.RLock()
+	post, exists := cm.posts[slug]
+	history, cached := cm.history[slug]
+	cm.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("post %q not found", slug)
+	}
+	if cached {
+		return history, nil
+	}
+
+	commits, err := cm.fetchCommits(post.Path, historyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch history for %s: %w", post.Path, err)
+	}
+
+	history = make([]Revision, 0, len(commits))
+	for _, commit := range commits {
+		history = append(history, Revision{
+			SHA:     commit.SHA,
+			Author:  commit.Commit.Author.Name,
+			Date:    commit.Commit.Author.Date,
+			Message: commit.Commit.Message,
+		})
+	}
+
+	cm.Lock()
+	cm.history[slug] = history
+	cm.Unlock()
+
+	return history, nil
+}
+
+// GetRevisionDiff returns a word-level diff of the post body between two
+// commits. An empty from compares against an empty document.
+func (cm *ContentManager) GetRevisionDiff(slug, from, to string) ([]DiffSegment, error) {
+	post, exists := cm.GetBySlug(slug)
+	if !exists {
+		return nil, fmt.Errorf("post %q not found", slug)
+	}
+
+	var before string
+	if from != "" {
+		content, err := cm.revisionContent(post.Path, from)
+		if err != nil {
+			return nil, err
+		}
+		before = content
+	}
+
+	after, err := cm.revisionContent(post.Path, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffWords(before, after), nil
+}
+
+// revisionContent returns the post body, without frontmatter, as it was at
+// the given commit. Contents at a commit never change, so they are cached
+// across refreshes.
+func (cm *ContentManager) revisionContent(path, sha string) (string, error) {
+	key := path + "@" + sha
+
+	cm.RLock()
+	body, cached := cm.revisions[key]
+	cm.RUnlock()
+
+	if cached {
+		return body, nil
+	}
+
+	content, err := cm.fetchFileContentAt(path, sha)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s at %s: %w", path, sha, err)
+	}
+
+	body = content
+	if _, stripped, err := parseFrontMatter([]byte(content)); err == nil {
+		body = stripped
+	}
+	body = strings.TrimSpace(body)
+
+	cm.Lock()
+	cm.revisions[key] = body
+	cm.Unlock()
+
+	return body, nil
+}
+
+// diffWords diffs two texts treating each word, run of whitespace and
+// punctuation mark as a single token, so changes line up with what readers
+// see.
+func diffWords(before, after string) []DiffSegment {
+	tokens := make(map[string]rune)
+	var lookup []string
+
+	encode := func(text string) []rune {
+		var encoded []rune
+		for _, token := range splitWords(text) {
+			r, seen := tokens[token]
+			if !seen {
+				r = tokenRune(len(lookup))
+				tokens[token] = r
+				lookup = append(lookup, token)
+			}
+			encoded = append(encoded, r)
+		}
+		return encoded
+	}
+
+	// Clean up while still encoded so edits stay aligned to whole tokens
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(encode(before), encode(after), false)
+	diffs = dmp.DiffCleanupSemantic(diffs)
+
+	segments := make([]DiffSegment, 0, len(diffs))
+	for _, diff := range diffs {
+		if diff.Text == "" {
+			continue
+		}
+
+		var text strings.Builder
+		for _, r := range diff.Text {
+			text.WriteString(lookup[runeToken(r)])
+		}
+
+		op := DiffEqual
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			op = DiffInsert
+		case diffmatchpatch.DiffDelete:
+			op = DiffDelete
+		}
+		segments = append(segments, DiffSegment{Op: op, Text: text.String()})
+	}
+
+	return segments
+}
+
+// splitWords breaks text into word, whitespace and punctuation tokens.
+func splitWords(text string) []string {
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 0
+		case unicode.IsSpace(r):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	var tokens []string
+	start, prev := 0, -1
+
+	for i, r := range text {
+		c := class(r)
+		if i > 0 && (c != prev || c == 2) {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+
+	return tokens
+}
+
+// tokenRune maps a token index onto a valid rune, skipping control
+// characters and the surrogate range so the diff library can round-trip it
+// through strings.
+func tokenRune(index int) rune {
+	r := rune(index) + 0x100
+	if r >= 0xD800 {
+		r += 0x800
+	}
+	return r
+}
+
+func runeToken(r rune) int {
+	if r >= 0xE000 {
+		r -= 0x800
+	}
+	return int(r - 0x100)
+}
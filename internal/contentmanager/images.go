@@ -0,0 +1,306 @@
+package contentmanager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"math"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxImagePixels is the largest image, in pixels, that is decoded for
+// resizing. Larger images are served as they are.
+const maxImagePixels = 50_000_000
+
+// resizableTypes are the images resized for srcset. GIFs, which may be
+// animated, and SVGs are served as they are.
+var resizableTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+// imageOptions configures the responsive variants made of post images.
+type imageOptions struct {
+	widths   []int
+	sizes    string
+	cacheDir string
+}
+
+// ResponsiveImages resizes the PNG, JPEG and WebP images posts reference
+// from the repository to each of widths, a comma separated list of pixel
+// widths, and lists the variants in the images' srcset. sizes is the sizes
+// attribute for the images. Variants are cached in cacheDir so they survive
+// restarts; leave it empty to keep them in memory only.
+func (cm *ContentManager) ResponsiveImages(widths, sizes, cacheDir string) error {
+	var parsed []int
+	for _, field := range strings.Split(widths, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		width, err := strconv.Atoi(field)
+		if err != nil || width <= 0 {
+			return fmt.Errorf("invalid image width %q", field)
+		}
+		parsed = append(parsed, width)
+	}
+	sort.Ints(parsed)
+
+	if cacheDir != "" {
+		if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create image cache: %w", err)
+		}
+	}
+
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.images = imageOptions{widths: parsed, sizes: sizes, cacheDir: cacheDir}
+
+	return nil
+}
+
+// responsiveImage adds the srcset, sizes and dimensions of a post image
+// served from the repository, making the resized variants it lists.
+func (t *assetTransformer) responsiveImage(n *ast.Image, post Post, name string, asset *Asset, collected *assetCollector) {
+	if !resizableTypes[asset.ContentType] {
+		return
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(asset.Data))
+	if err != nil {
+		log.Printf("Failed to read image %s for %s: %v", asset.Path, post.Path, err)
+		return
+	}
+	if config.Width*config.Height > maxImagePixels {
+		log.Printf("Not resizing image %s for %s: larger than %d pixels", asset.Path, post.Path, maxImagePixels)
+		setDimensions(n, config.Width, config.Height)
+		return
+	}
+
+	widths := variantWidths(config.Width, collected.images.widths)
+	if len(widths) == 0 {
+		setDimensions(n, config.Width, config.Height)
+		return
+	}
+
+	var decoded image.Image
+	var srcset []string
+	var src string
+	for _, width := range widths {
+		// The original is the full width entry, so it isn't re-encoded
+		if width == config.Width {
+			src = AssetURL(post.Slug, name, asset)
+			srcset = append(srcset, src+" "+strconv.Itoa(width)+"w")
+			continue
+		}
+
+		variantName := fmt.Sprintf("%s/%dw", name, width)
+		key := assetKey(post.Slug, variantName)
+
+		variant, ok := collected.assets[key]
+		if !ok {
+			if previous := collected.previous[key]; previous != nil && previous.source == asset.Hash {
+				variant = previous
+			} else if variant, ok = collected.images.cached(asset, width); !ok {
+				if decoded == nil {
+					if decoded, _, err = image.Decode(bytes.NewReader(asset.Data)); err != nil {
+						log.Printf("Failed to decode image %s for %s: %v", asset.Path, post.Path, err)
+						setDimensions(n, config.Width, config.Height)
+						return
+					}
+				}
+				if variant, err = resizeImage(decoded, asset, width); err != nil {
+					log.Printf("Failed to resize image %s for %s: %v", asset.Path, post.Path, err)
+					setDimensions(n, config.Width, config.Height)
+					return
+				}
+				collected.images.store(variant)
+			}
+			collected.assets[key] = variant
+		}
+
+		src = AssetURL(post.Slug, variantName, variant)
+		srcset = append(srcset, src+" "+strconv.Itoa(width)+"w")
+	}
+
+	// The largest variant is the fallback src, so its size is the one given
+	largest := widths[len(widths)-1]
+	n.Destination = []byte(src)
+	setDimensions(n, largest, scaledHeight(config.Width, config.Height, largest))
+	n.SetAttributeString("srcset", []byte(strings.Join(srcset, ", ")))
+	if collected.images.sizes != "" {
+		n.SetAttributeString("sizes", []byte(collected.images.sizes))
+	}
+}
+
+// setDimensions gives an image its size so the page doesn't shift as it
+// loads.
+func setDimensions(n *ast.Image, width, height int) {
+	n.SetAttributeString("width", []byte(strconv.Itoa(width)))
+	n.SetAttributeString("height", []byte(strconv.Itoa(height)))
+}
+
+// variantWidths returns the configured widths narrower than the original,
+// followed by the original width, so images are never enlarged. An image no
+// wider than the smallest width gets no variants at all.
+func variantWidths(original int, widths []int) []int {
+	var result []int
+	for _, width := range widths {
+		if width >= original {
+			break
+		}
+		result = append(result, width)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return append(result, original)
+}
+
+// scaledHeight is the height of an image resized to width.
+func scaledHeight(originalWidth, originalHeight, width int) int {
+	return max(1, int(math.Round(float64(originalHeight)*float64(width)/float64(originalWidth))))
+}
+
+// resizeImage scales img to width. PNGs stay PNGs, so screenshots and line
+// art keep sharp edges, and other images become JPEGs unless they have
+// transparency.
+func resizeImage(img image.Image, source *Asset, width int) (*Asset, error) {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, scaledHeight(bounds.Dx(), bounds.Dy(), width)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	ext := ".jpg"
+	if source.ContentType != "image/png" && dst.Opaque() {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 82}); err != nil {
+			return nil, err
+		}
+	} else {
+		ext = ".png"
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return newVariant(source, width, ext, buf.Bytes()), nil
+}
+
+// newVariant makes the Asset for a copy of source resized to width.
+func newVariant(source *Asset, width int, ext string, data []byte) *Asset {
+	sum := sha256.Sum256(data)
+
+	return &Asset{
+		Path:        source.Path,
+		ContentType: mime.TypeByExtension(ext),
+		Data:        data,
+		Hash:        hex.EncodeToString(sum[:6]),
+		source:      source.Hash,
+		cacheName:   cacheName(source.Hash, width, ext),
+	}
+}
+
+// cacheVariant matches the names of the files in the image cache.
+var cacheVariant = regexp.MustCompile(`^[0-9a-f]+-[0-9]+\.(jpg|png)$`)
+
+// cacheName is the name of the file in the image cache holding the variant
+// at width of the image with hash.
+func cacheName(hash string, width int, ext string) string {
+	return fmt.Sprintf("%s-%d%s", hash, width, ext)
+}
+
+// cached returns a variant resized in an earlier run. Variants are named
+// after the content hash of the image they were made from, so an image that
+// changes is resized again.
+func (o imageOptions) cached(source *Asset, width int) (*Asset, bool) {
+	if o.cacheDir == "" {
+		return nil, false
+	}
+
+	// Resized PNGs are always PNGs
+	exts := []string{".jpg", ".png"}
+	if source.ContentType == "image/png" {
+		exts = exts[1:]
+	}
+
+	for _, ext := range exts {
+		if data, err := os.ReadFile(filepath.Join(o.cacheDir, cacheName(source.Hash, width, ext))); err == nil {
+			return newVariant(source, width, ext, data), true
+		}
+	}
+	return nil, false
+}
+
+// store writes a variant to the cache, so it needn't be resized again after
+// a restart.
+func (o imageOptions) store(variant *Asset) {
+	if o.cacheDir == "" {
+		return
+	}
+
+	// Write to a temporary file first so a partial write is never read
+	tmp, err := os.CreateTemp(o.cacheDir, "*.tmp")
+	if err != nil {
+		log.Printf("Failed to cache resized image: %v", err)
+		return
+	}
+	_, err = tmp.Write(variant.Data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(o.cacheDir, variant.cacheName))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("Failed to cache resized image: %v", err)
+	}
+}
+
+// prune removes the cached variants that none of assets use, such as those
+// of images that changed or were removed, so the cache doesn't keep growing.
+// Other files in the cache directory are left alone.
+func (o imageOptions) prune(assets map[string]*Asset) {
+	if o.cacheDir == "" {
+		return
+	}
+
+	used := make(map[string]bool)
+	for _, asset := range assets {
+		if asset.cacheName != "" {
+			used[asset.cacheName] = true
+		}
+	}
+
+	entries, err := os.ReadDir(o.cacheDir)
+	if err != nil {
+		log.Printf("Failed to prune image cache: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || used[entry.Name()] || !cacheVariant.MatchString(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(o.cacheDir, entry.Name())); err != nil {
+			log.Printf("Failed to prune image cache: %v", err)
+		}
+	}
+}
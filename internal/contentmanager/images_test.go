@@ -0,0 +1,104 @@
+package contentmanager
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVariantWidths(t *testing.T) {
+	tests := []struct {
+		name     string
+		original int
+		want     []int
+	}{
+		{"wider than every width", 2000, []int{480, 960, 1440, 2000}},
+		{"between widths", 1000, []int{480, 960, 1000}},
+		{"equal to a width", 960, []int{480, 960}},
+		{"no wider than the smallest", 480, nil},
+		{"narrower than every width", 300, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := variantWidths(test.original, []int{480, 960, 1440}); !slices.Equal(got, test.want) {
+				t.Errorf("variantWidths(%d) = %v, want %v", test.original, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResponsiveImages(t *testing.T) {
+	screenshot := image.NewNRGBA(image.Rect(0, 0, 1200, 600))
+	for x := range 1200 {
+		screenshot.Set(x, x%600, color.Black)
+	}
+	var original bytes.Buffer
+	if err := png.Encode(&original, screenshot); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := t.TempDir()
+	cm := New("owner", "repo")
+	cm.client = &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(original.Bytes())),
+			Header:     make(http.Header),
+		}, nil
+	})}
+	if err := cm.ResponsiveImages("480,960,1920", "", cacheDir); err != nil {
+		t.Fatal(err)
+	}
+
+	p := cm.newPipeline()
+	post := Post{Path: "test.md", Slug: "test"}
+	if err := p.render(&post, []byte("![Screenshot](screenshot.png)\n")); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	// The original is the widest entry and the src, served as it is
+	full := "/posts/test/assets/screenshot.png?v=" + p.assets.assets["test/screenshot.png"].Hash + " 1200w"
+	for _, want := range []string{"screenshot.png/480w?v=", "screenshot.png/960w?v=", full, `width="1200"`} {
+		if !strings.Contains(post.Content, want) {
+			t.Errorf("expected %s in:\n%s", want, post.Content)
+		}
+	}
+	if strings.Contains(post.Content, "1200w?v=") {
+		t.Errorf("the original was re-encoded:\n%s", post.Content)
+	}
+
+	for _, width := range []string{"480w", "960w"} {
+		variant := p.assets.assets["test/screenshot.png/"+width]
+		if variant == nil || variant.ContentType != "image/png" {
+			t.Fatalf("variant %s = %+v, want a PNG", width, variant)
+		}
+	}
+
+	// Only variants no post uses are pruned
+	if err := os.WriteFile(filepath.Join(cacheDir, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	kept := p.assets.assets["test/screenshot.png/960w"]
+	p.assets.images.prune(map[string]*Asset{"test/screenshot.png/960w": kept})
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{kept.cacheName, "notes.txt"}; !slices.Equal(names, want) {
+		t.Errorf("cache holds %v after pruning, want %v", names, want)
+	}
+}
@@ -0,0 +1,102 @@
+package contentmanager
+
+import (
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// slugsKey holds the map of repository paths to post slugs in the
+// parser.Context, so links between posts can be resolved.
+var slugsKey = parser.NewContextKey()
+
+// linkTransformer rewrites links as the markdown is parsed:
+//
+//   - links to other sites open in a new tab without passing on the page
+//     that opened them
+//   - relative links to markdown files in the posts repository point at
+//     that post's page, keeping any #fragment
+//   - in-page anchors and other site paths are left alone
+type linkTransformer struct{}
+
+func (t *linkTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	post, _ := PostFromContext(pc)
+	slugs, _ := pc.Get(slugsKey).(map[string]string)
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch link := n.(type) {
+		case *ast.Link:
+			destination := string(link.Destination)
+			switch {
+			case isExternalLink(destination):
+				markExternal(link)
+			case isMarkdownLink(destination):
+				if resolved, ok := resolvePostLink(post.Path, destination, slugs); ok {
+					link.Destination = []byte(resolved)
+				} else {
+					log.Printf("Link to %s in %s doesn't match a published post", destination, post.Path)
+				}
+			}
+		case *ast.AutoLink:
+			if link.AutoLinkType == ast.AutoLinkURL {
+				markExternal(link)
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+func markExternal(link ast.Node) {
+	link.SetAttributeString("target", []byte("_blank"))
+	link.SetAttributeString("rel", []byte("noopener noreferrer"))
+}
+
+// isExternalLink reports whether destination points at another site.
+func isExternalLink(destination string) bool {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return false
+	}
+	return u.Host != "" && (u.Scheme == "" || u.Scheme == "http" || u.Scheme == "https")
+}
+
+// isMarkdownLink reports whether destination is a relative link to a
+// markdown file.
+func isMarkdownLink(destination string) bool {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".md")
+}
+
+// resolvePostLink turns a link to a markdown file, relative to the post at
+// from, into the /posts/:slug URL of that post.
+func resolvePostLink(from, destination string, slugs map[string]string) (string, bool) {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return "", false
+	}
+
+	target := path.Join(path.Dir(from), u.Path)
+	slug, ok := slugs[target]
+	if !ok {
+		return "", false
+	}
+
+	resolved := "/posts/" + url.PathEscape(slug)
+	if u.Fragment != "" {
+		resolved += "#" + u.EscapedFragment()
+	}
+	return resolved, true
+}
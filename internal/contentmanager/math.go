@@ -0,0 +1,220 @@
+package contentmanager
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Math is a TeX formula written inline as $...$ or $$...$$.
+type Math struct {
+	ast.BaseInline
+	TeX     string
+	Display bool
+}
+
+// KindMath is the NodeKind of Math nodes.
+var KindMath = ast.NewNodeKind("Math")
+
+// Kind implements ast.Node.
+func (n *Math) Kind() ast.NodeKind {
+	return KindMath
+}
+
+// Dump implements ast.Node.
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": n.TeX}, nil)
+}
+
+// MathBlock is a TeX formula between $$ lines.
+type MathBlock struct {
+	ast.BaseBlock
+}
+
+// KindMathBlock is the NodeKind of MathBlock nodes.
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+// Kind implements ast.Node.
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+// IsRaw implements ast.Node.
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+// Dump implements ast.Node.
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// mathExtension renders TeX math to MathML at parse time, so formulas
+// display without client-side JavaScript.
+type mathExtension struct{}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 90)),
+		parser.WithInlineParsers(util.Prioritized(&mathParser{}, 90)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 500)),
+	)
+}
+
+// mathParser parses $inline$ and $$display$$ math within a line. To avoid
+// catching prices like "$5 and $10", inline math can't start or end with a
+// space or be followed by a digit.
+type mathParser struct{}
+
+func (p *mathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+
+	if bytes.HasPrefix(line, []byte("$$")) {
+		end := bytes.Index(line[2:], []byte("$$"))
+		if end <= 0 {
+			return nil
+		}
+		block.Advance(end + 4)
+		return &Math{TeX: string(line[2 : end+2]), Display: true}
+	}
+
+	if len(line) < 3 || line[1] == ' ' || line[1] == '\t' {
+		return nil
+	}
+	for i := 2; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '$':
+			if line[i-1] == ' ' || line[i-1] == '\t' || i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9' {
+				return nil
+			}
+			block.Advance(i + 1)
+			return &Math{TeX: string(line[1:i])}
+		}
+	}
+
+	return nil
+}
+
+// mathBlockParser parses display math fenced by $$ lines:
+//
+//	$$
+//	\sum_{i=1}^{n} x_i
+//	$$
+type mathBlockParser struct{}
+
+func (b *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (b *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if !bytes.Equal(bytes.TrimSpace(line), []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+	reader.Advance(withoutNewline(line, segment))
+
+	return &MathBlock{}, parser.NoChildren
+}
+
+func (b *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if bytes.Equal(bytes.TrimSpace(line), []byte("$$")) {
+		reader.Advance(withoutNewline(line, segment))
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(withoutNewline(line, segment))
+	return parser.Continue | parser.NoChildren
+}
+
+// withoutNewline is the length of a line up to its newline, which the last
+// line of a post may not have. Leaving the newline lets the parser move on to
+// the next line.
+func withoutNewline(line []byte, segment text.Segment) int {
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		return segment.Len() - 1
+	}
+	return segment.Len()
+}
+
+func (b *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *mathRenderer) renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*Math)
+	writeMath(w, n.TeX, n.Display)
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var tex bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		tex.Write(line.Value(source))
+	}
+
+	_, _ = w.WriteString(`<div class="math-display">`)
+	writeMath(w, tex.String(), true)
+	_, _ = w.WriteString("</div>\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeMath writes the MathML for tex, or the TeX itself in a code element
+// when it can't be converted.
+func writeMath(w util.BufWriter, tex string, display bool) {
+	mathML, err := texToMathML(tex, display)
+	if err == nil {
+		_, _ = w.WriteString(mathML)
+		return
+	}
+
+	log.Printf("Failed to convert math %q: %v", tex, err)
+
+	delimiter := "$"
+	if display {
+		delimiter = "$$"
+	}
+	_, _ = w.WriteString(`<code class="math-fallback">`)
+	_, _ = w.Write(util.EscapeHTML([]byte(delimiter + tex + delimiter)))
+	_, _ = w.WriteString(`</code>`)
+}
@@ -0,0 +1,143 @@
+package contentmanager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTeXToMathML(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want string
+	}{
+		{`x^2`, `<msup><mi>x</mi><mrow><mn>2</mn></mrow></msup>`},
+		{`x_i`, `<msub><mi>x</mi>`},
+		{`\frac{a}{b}`, `<mfrac>`},
+		{`\sqrt{x}`, `<msqrt><mrow><mi>x</mi></mrow></msqrt>`},
+		{`\sqrt[3]{x}`, `<mroot>`},
+		{`\alpha + \beta`, `<mi>α</mi><mo>+</mo><mi>β</mi>`},
+		{`\sum_{i=1}^n i`, `<mo largeop="true">∑</mo>`},
+		{`\text{if } x`, `<mtext>if</mtext>`},
+		{`\left( x \right)`, `<mo fence="true" stretchy="true">(</mo>`},
+		{`\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, `<mtable><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr>`},
+		{`\mathbb{R}`, `<mi>ℝ</mi>`},
+		{`\hat{x}`, `<mover accent="true">`},
+		{`3.14`, `<mn>3.14</mn>`},
+		{`a < b`, `<annotation encoding="application/x-tex">a &lt; b</annotation>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tex, func(t *testing.T) {
+			got, err := texToMathML(test.tex, false)
+			if err != nil {
+				t.Fatalf("texToMathML(%q) failed: %v", test.tex, err)
+			}
+			if !strings.Contains(got, test.want) {
+				t.Errorf("texToMathML(%q) = %s, want it to contain %s", test.tex, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTeXToMathMLErrors(t *testing.T) {
+	for _, tex := range []string{
+		`\unknown`,
+		`\frac{a}`,
+		`x^`,
+		`{x`,
+		`\left( x`,
+		`\begin{foo} x \end{foo}`,
+	} {
+		t.Run(tex, func(t *testing.T) {
+			if got, err := texToMathML(tex, false); err == nil {
+				t.Errorf("texToMathML(%q) = %s, want an error", tex, got)
+			}
+		})
+	}
+}
+
+func TestMathMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "block without trailing newline",
+			body:    "$$\n\\sum_{i=1}^n i\n$$",
+			want:    []string{`<div class="math-display"><math`, `display="block"`},
+			notWant: []string{"<p>"},
+		},
+		{
+			name:    "block with trailing newline",
+			body:    "$$\n\\sum_{i=1}^n i\n$$\n\nafter\n",
+			want:    []string{`<div class="math-display"><math`, "<p>after</p>"},
+			notWant: []string{"<p>$"},
+		},
+		{
+			name:    "unclosed block at end of post",
+			body:    "$$\nx^2",
+			want:    []string{`<msup>`},
+			notWant: []string{"<p>"},
+		},
+		{
+			name: "inline",
+			body: "where $x^2$ grows",
+			want: []string{"<p>where <math", "</math> grows</p>"},
+		},
+		{
+			name: "inline at end of post",
+			body: "ends with $x$",
+			want: []string{"<p>ends with <math"},
+		},
+		{
+			name:    "prices",
+			body:    "costs $5 and $10",
+			want:    []string{"<p>costs $5 and $10</p>"},
+			notWant: []string{"<math"},
+		},
+		{
+			name:    "escaped dollar",
+			body:    `\$x$ stays`,
+			notWant: []string{"<math"},
+		},
+		{
+			name:    "inline fallback",
+			body:    `inline $\unknown$ x`,
+			want:    []string{`<code class="math-fallback">$\unknown$</code>`},
+			notWant: []string{"<math"},
+		},
+		{
+			name:    "block fallback",
+			body:    "$$\n\\unknown\n$$",
+			want:    []string{`<div class="math-display"><code class="math-fallback">$$\unknown`},
+			notWant: []string{"<math"},
+		},
+	}
+
+	cm := New("owner", "repo")
+	if err := cm.EnableMarkdownExtensions("math"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			post := Post{Path: "test.md", Slug: "test"}
+			if err := cm.newPipeline().render(&post, []byte(test.body)); err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+
+			for _, want := range test.want {
+				if !strings.Contains(post.Content, want) {
+					t.Errorf("expected %s in:\n%s", want, post.Content)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(post.Content, notWant) {
+					t.Errorf("didn't expect %s in:\n%s", notWant, post.Content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package contentmanager
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// texToMathML converts a TeX expression into a MathML <math> element. It
+// understands the subset of LaTeX posts use for formulas: scripts, fractions,
+// roots, accents, greek letters, common operators and relations, \text,
+// \left...\right and matrix-like environments. Anything else is an error so
+// the caller can fall back to showing the TeX itself.
+func texToMathML(tex string, display bool) (string, error) {
+	p := &texParser{tokens: tokenizeTeX(tex), display: display}
+
+	body, err := p.parseRow(nil)
+	if err != nil {
+		return "", err
+	}
+	if p.peek() != "" {
+		return "", fmt.Errorf("unexpected %q", p.peek())
+	}
+
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML"`)
+	if display {
+		b.WriteString(` display="block"`)
+	}
+	b.WriteString(`><semantics><mrow>`)
+	b.WriteString(body)
+	b.WriteString(`</mrow><annotation encoding="application/x-tex">`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(tex)))
+	b.WriteString(`</annotation></semantics></math>`)
+
+	return b.String(), nil
+}
+
+// tokenizeTeX splits TeX into commands (\frac, \{, \\), single characters,
+// runs of digits and single " " tokens for runs of whitespace, which only
+// matter inside \text.
+func tokenizeTeX(tex string) []string {
+	var tokens []string
+
+	for i := 0; i < len(tex); {
+		r, size := utf8.DecodeRuneInString(tex[i:])
+		switch {
+		case r == '\\':
+			j := i + 1
+			for j < len(tex) && isASCIILetter(tex[j]) {
+				j++
+			}
+			if j == i+1 && j < len(tex) {
+				// Control symbols like \{ \, \\ are a single character
+				_, n := utf8.DecodeRuneInString(tex[j:])
+				j += n
+			}
+			tokens = append(tokens, tex[i:j])
+			i = j
+		case unicode.IsSpace(r):
+			for i < len(tex) && unicode.IsSpace(r) {
+				i += size
+				r, size = utf8.DecodeRuneInString(tex[i:])
+			}
+			tokens = append(tokens, " ")
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(tex) && (tex[j] >= '0' && tex[j] <= '9' || tex[j] == '.' && j+1 < len(tex) && tex[j+1] >= '0' && tex[j+1] <= '9') {
+				j++
+			}
+			tokens = append(tokens, tex[i:j])
+			i = j
+		default:
+			tokens = append(tokens, tex[i:i+size])
+			i += size
+		}
+	}
+
+	return tokens
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+var texIdentifiers = map[string]string{
+	`\alpha`: "α", `\beta`: "β", `\gamma`: "γ", `\delta`: "δ", `\epsilon`: "ϵ", `\varepsilon`: "ε",
+	`\zeta`: "ζ", `\eta`: "η", `\theta`: "θ", `\vartheta`: "ϑ", `\iota`: "ι", `\kappa`: "κ",
+	`\lambda`: "λ", `\mu`: "μ", `\nu`: "ν", `\xi`: "ξ", `\pi`: "π", `\varpi`: "ϖ", `\rho`: "ρ",
+	`\varrho`: "ϱ", `\sigma`: "σ", `\varsigma`: "ς", `\tau`: "τ", `\upsilon`: "υ", `\phi`: "ϕ",
+	`\varphi`: "φ", `\chi`: "χ", `\psi`: "ψ", `\omega`: "ω",
+	`\Gamma`: "Γ", `\Delta`: "Δ", `\Theta`: "Θ", `\Lambda`: "Λ", `\Xi`: "Ξ", `\Pi`: "Π",
+	`\Sigma`: "Σ", `\Upsilon`: "Υ", `\Phi`: "Φ", `\Psi`: "Ψ", `\Omega`: "Ω",
+	`\infty`: "∞", `\partial`: "∂", `\nabla`: "∇", `\ell`: "ℓ", `\hbar`: "ℏ", `\emptyset`: "∅",
+}
+
+var texOperators = map[string]string{
+	`\cdot`: "⋅", `\times`: "×", `\div`: "÷", `\pm`: "±", `\mp`: "∓", `\ast`: "∗", `\circ`: "∘",
+	`\le`: "≤", `\leq`: "≤", `\ge`: "≥", `\geq`: "≥", `\ne`: "≠", `\neq`: "≠", `\ll`: "≪", `\gg`: "≫",
+	`\approx`: "≈", `\sim`: "∼", `\simeq`: "≃", `\equiv`: "≡", `\propto`: "∝",
+	`\to`: "→", `\rightarrow`: "→", `\leftarrow`: "←", `\gets`: "←", `\mapsto`: "↦",
+	`\Rightarrow`: "⇒", `\Leftarrow`: "⇐", `\Leftrightarrow`: "⇔", `\iff`: "⇔", `\implies`: "⇒",
+	`\in`: "∈", `\notin`: "∉", `\ni`: "∋", `\subset`: "⊂", `\subseteq`: "⊆", `\supset`: "⊃",
+	`\supseteq`: "⊇", `\cup`: "∪", `\cap`: "∩", `\setminus`: "∖", `\forall`: "∀", `\exists`: "∃",
+	`\neg`: "¬", `\land`: "∧", `\wedge`: "∧", `\lor`: "∨", `\vee`: "∨", `\oplus`: "⊕",
+	`\ldots`: "…", `\dots`: "…", `\cdots`: "⋯", `\vdots`: "⋮", `\ddots`: "⋱",
+	`\lceil`: "⌈", `\rceil`: "⌉", `\lfloor`: "⌊", `\rfloor`: "⌋", `\langle`: "⟨", `\rangle`: "⟩",
+	`\mid`: "∣", `\|`: "‖", `\{`: "{", `\}`: "}", `\%`: "%", `\_`: "_", `\#`: "#", `\&`: "&", `\$`: "$",
+}
+
+// texLargeOperators take limits above and below in display math.
+var texLargeOperators = map[string]string{
+	`\sum`: "∑", `\prod`: "∏", `\coprod`: "∐", `\bigcup`: "⋃", `\bigcap`: "⋂",
+	`\int`: "∫", `\iint`: "∬", `\oint`: "∮",
+}
+
+// texFunctions are upright function names; those marked true take limits
+// like large operators.
+var texFunctions = map[string]bool{
+	`\lim`: true, `\max`: true, `\min`: true, `\sup`: true, `\inf`: true, `\argmax`: true, `\argmin`: true,
+	`\log`: false, `\ln`: false, `\lg`: false, `\exp`: false, `\sin`: false, `\cos`: false, `\tan`: false,
+	`\sec`: false, `\csc`: false, `\cot`: false, `\sinh`: false, `\cosh`: false, `\tanh`: false,
+	`\arcsin`: false, `\arccos`: false, `\arctan`: false, `\det`: false, `\dim`: false, `\gcd`: false,
+	`\deg`: false, `\Pr`: false,
+}
+
+var texSpaces = map[string]string{
+	`\,`: "0.1667em", `\:`: "0.2222em", `\>`: "0.2222em", `\;`: "0.2778em", `\ `: "0.25em",
+	`\quad`: "1em", `\qquad`: "2em", `\!`: "-0.1667em",
+}
+
+var texAccents = map[string]string{
+	`\hat`: "^", `\widehat`: "^", `\bar`: "¯", `\overline`: "¯", `\vec`: "→", `\tilde`: "~",
+	`\widetilde`: "~", `\dot`: "˙", `\ddot`: "¨",
+}
+
+var texDoubleStruck = map[rune]string{
+	'C': "ℂ", 'H': "ℍ", 'N': "ℕ", 'P': "ℙ", 'Q': "ℚ", 'R': "ℝ", 'Z': "ℤ",
+}
+
+// texMatrixFences are the delimiters around each supported environment.
+var texMatrixFences = map[string][2]string{
+	"matrix":  {"", ""},
+	"pmatrix": {"(", ")"},
+	"bmatrix": {"[", "]"},
+	"Bmatrix": {"{", "}"},
+	"vmatrix": {"|", "|"},
+	"cases":   {"{", ""},
+	"aligned": {"", ""},
+	"align":   {"", ""},
+	"align*":  {"", ""},
+	"array":   {"", ""},
+}
+
+type texParser struct {
+	tokens  []string
+	pos     int
+	display bool
+}
+
+func (p *texParser) peek() string {
+	for p.pos < len(p.tokens) && p.tokens[p.pos] == " " {
+		p.pos++
+	}
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *texParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *texParser) expect(token string) error {
+	if got := p.next(); got != token {
+		if got == "" {
+			return fmt.Errorf("expected %q before end of expression", token)
+		}
+		return fmt.Errorf("expected %q, found %q", token, got)
+	}
+	return nil
+}
+
+// parseRow parses atoms until the end of input or one of the stop tokens,
+// which is left unread.
+func (p *texParser) parseRow(stop map[string]bool) (string, error) {
+	var b strings.Builder
+
+	for p.peek() != "" && !stop[p.peek()] {
+		if p.peek() == `\over` {
+			return "", fmt.Errorf(`\over is not supported, use \frac`)
+		}
+
+		atom, limits, err := p.parseAtom()
+		if err != nil {
+			return "", err
+		}
+
+		atom, err = p.parseScripts(atom, limits)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(atom)
+	}
+
+	return b.String(), nil
+}
+
+// parseScripts attaches any ^ and _ following atom.
+func (p *texParser) parseScripts(atom string, limits bool) (string, error) {
+	var sub, sup string
+
+	for p.peek() == "^" || p.peek() == "_" || p.peek() == "'" {
+		token := p.next()
+
+		if token == "'" {
+			sup += "<mo>′</mo>"
+			continue
+		}
+
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", err
+		}
+		if token == "^" {
+			if sup != "" && !strings.HasPrefix(sup, "<mo>′") {
+				return "", fmt.Errorf("double superscript")
+			}
+			sup += arg
+		} else {
+			if sub != "" {
+				return "", fmt.Errorf("double subscript")
+			}
+			sub = arg
+		}
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if limits && p.display {
+		under, over, both = "munder", "mover", "munderover"
+	}
+
+	switch {
+	case sub != "" && sup != "":
+		return fmt.Sprintf("<%s>%s<mrow>%s</mrow><mrow>%s</mrow></%s>", both, atom, sub, sup, both), nil
+	case sub != "":
+		return fmt.Sprintf("<%s>%s<mrow>%s</mrow></%s>", under, atom, sub, under), nil
+	case sup != "":
+		return fmt.Sprintf("<%s>%s<mrow>%s</mrow></%s>", over, atom, sup, over), nil
+	}
+
+	return atom, nil
+}
+
+// parseArgument parses a command argument or script: a braced group or a
+// single atom.
+func (p *texParser) parseArgument() (string, error) {
+	if p.peek() == "" {
+		return "", fmt.Errorf("missing argument")
+	}
+	atom, _, err := p.parseAtom()
+	return atom, err
+}
+
+// parseGroupText reads a braced argument as literal text, for \text.
+func (p *texParser) parseGroupText() (string, error) {
+	if err := p.expect("{"); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		token := p.tokens[p.pos]
+		switch token {
+		case "{":
+			depth++
+		case "}":
+			if depth == 0 {
+				p.pos++
+				return strings.TrimSpace(b.String()), nil
+			}
+			depth--
+		}
+		if token != "{" && token != "}" {
+			b.WriteString(strings.TrimPrefix(token, `\`))
+		}
+	}
+
+	return "", fmt.Errorf("unclosed group")
+}
+
+// parseAtom parses one element and reports whether it takes limits above
+// and below in display math.
+func (p *texParser) parseAtom() (string, bool, error) {
+	token := p.next()
+
+	switch {
+	case token == "{":
+		row, err := p.parseRow(map[string]bool{"}": true})
+		if err != nil {
+			return "", false, err
+		}
+		if err := p.expect("}"); err != nil {
+			return "", false, err
+		}
+		return "<mrow>" + row + "</mrow>", false, nil
+	case token == "}" || token == "&" || token == `\\`:
+		return "", false, fmt.Errorf("unexpected %q", token)
+	case token == "^" || token == "_":
+		return "", false, fmt.Errorf("script %q without a base", token)
+	case token[0] >= '0' && token[0] <= '9':
+		return "<mn>" + token + "</mn>", false, nil
+	case token[0] != '\\':
+		r, _ := utf8.DecodeRuneInString(token)
+		if unicode.IsLetter(r) {
+			return "<mi>" + html.EscapeString(token) + "</mi>", false, nil
+		}
+		switch token {
+		case "-":
+			token = "−"
+		case "*":
+			token = "∗"
+		case "~":
+			return `<mspace width="0.25em"/>`, false, nil
+		}
+		return "<mo>" + html.EscapeString(token) + "</mo>", false, nil
+	}
+
+	if symbol, ok := texIdentifiers[token]; ok {
+		return "<mi>" + symbol + "</mi>", false, nil
+	}
+	if symbol, ok := texOperators[token]; ok {
+		return "<mo>" + html.EscapeString(symbol) + "</mo>", false, nil
+	}
+	if symbol, ok := texLargeOperators[token]; ok {
+		limits := !strings.Contains(token, "int")
+		return `<mo largeop="true">` + symbol + "</mo>", limits, nil
+	}
+	if limits, ok := texFunctions[token]; ok {
+		return `<mi mathvariant="normal">` + token[1:] + "</mi>", limits, nil
+	}
+	if width, ok := texSpaces[token]; ok {
+		return `<mspace width="` + width + `"/>`, false, nil
+	}
+	if accent, ok := texAccents[token]; ok {
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return `<mover accent="true"><mrow>` + arg + "</mrow><mo>" + accent + "</mo></mover>", false, nil
+	}
+
+	switch token {
+	case `\frac`, `\dfrac`, `\tfrac`, `\binom`:
+		num, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		den, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		if token == `\binom` {
+			return `<mrow><mo>(</mo><mfrac linethickness="0"><mrow>` + num + "</mrow><mrow>" + den + "</mrow></mfrac><mo>)</mo></mrow>", false, nil
+		}
+		return "<mfrac><mrow>" + num + "</mrow><mrow>" + den + "</mrow></mfrac>", false, nil
+	case `\sqrt`:
+		var index string
+		if p.peek() == "[" {
+			p.next()
+			row, err := p.parseRow(map[string]bool{"]": true})
+			if err != nil {
+				return "", false, err
+			}
+			if err := p.expect("]"); err != nil {
+				return "", false, err
+			}
+			index = row
+		}
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		if index != "" {
+			return "<mroot><mrow>" + arg + "</mrow><mrow>" + index + "</mrow></mroot>", false, nil
+		}
+		return "<msqrt>" + arg + "</msqrt>", false, nil
+	case `\text`, `\textrm`, `\mbox`:
+		text, err := p.parseGroupText()
+		if err != nil {
+			return "", false, err
+		}
+		return "<mtext>" + html.EscapeString(text) + "</mtext>", false, nil
+	case `\mathrm`, `\operatorname`:
+		text, err := p.parseGroupText()
+		if err != nil {
+			return "", false, err
+		}
+		return `<mi mathvariant="normal">` + html.EscapeString(text) + "</mi>", false, nil
+	case `\mathbb`:
+		text, err := p.parseGroupText()
+		if err != nil {
+			return "", false, err
+		}
+		letter, _ := utf8.DecodeRuneInString(text)
+		symbol, ok := texDoubleStruck[letter]
+		if !ok || utf8.RuneCountInString(text) != 1 {
+			return "", false, fmt.Errorf(`unsupported \mathbb{%s}`, text)
+		}
+		return "<mi>" + symbol + "</mi>", false, nil
+	case `\mathbf`, `\boldsymbol`:
+		arg, err := p.parseArgument()
+		if err != nil {
+			return "", false, err
+		}
+		return `<mrow class="math-bold">` + arg + "</mrow>", false, nil
+	case `\left`:
+		return p.parseFenced()
+	case `\begin`:
+		table, err := p.parseEnvironment()
+		return table, false, err
+	}
+
+	return "", false, fmt.Errorf("unsupported command %s", token)
+}
+
+// parseFenced parses \left( ... \right) into a row with stretchy fences.
+func (p *texParser) parseFenced() (string, bool, error) {
+	open, err := p.parseDelimiter()
+	if err != nil {
+		return "", false, err
+	}
+
+	row, err := p.parseRow(map[string]bool{`\right`: true})
+	if err != nil {
+		return "", false, err
+	}
+	if err := p.expect(`\right`); err != nil {
+		return "", false, err
+	}
+
+	closing, err := p.parseDelimiter()
+	if err != nil {
+		return "", false, err
+	}
+
+	return "<mrow>" + fence(open) + row + fence(closing) + "</mrow>", false, nil
+}
+
+func (p *texParser) parseDelimiter() (string, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return "", fmt.Errorf("missing delimiter")
+	case token == ".":
+		return "", nil
+	case token[0] != '\\':
+		return token, nil
+	}
+	if symbol, ok := texOperators[token]; ok {
+		return symbol, nil
+	}
+	return "", fmt.Errorf("unsupported delimiter %s", token)
+}
+
+func fence(delimiter string) string {
+	if delimiter == "" {
+		return ""
+	}
+	return `<mo fence="true" stretchy="true">` + html.EscapeString(delimiter) + "</mo>"
+}
+
+// parseEnvironment parses \begin{name} rows \\ separated by & \end{name}
+// into a table.
+func (p *texParser) parseEnvironment() (string, error) {
+	name, err := p.parseGroupText()
+	if err != nil {
+		return "", err
+	}
+	fences, ok := texMatrixFences[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported environment %s", name)
+	}
+	if name == "array" {
+		// Column alignment isn't rendered
+		if _, err := p.parseGroupText(); err != nil {
+			return "", err
+		}
+	}
+
+	align := ""
+	switch name {
+	case "cases":
+		align = ` columnalign="left"`
+	case "aligned", "align", "align*":
+		align = ` columnalign="right left" columnspacing="0"`
+	}
+
+	var b strings.Builder
+	b.WriteString("<mtable" + align + "><mtr><mtd>")
+
+	stop := map[string]bool{"&": true, `\\`: true, `\end`: true}
+	for {
+		cell, err := p.parseRow(stop)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(cell)
+
+		switch p.next() {
+		case "&":
+			b.WriteString("</mtd><mtd>")
+		case `\\`:
+			if p.peek() == `\end` {
+				continue
+			}
+			b.WriteString("</mtd></mtr><mtr><mtd>")
+		case `\end`:
+			closing, err := p.parseGroupText()
+			if err != nil {
+				return "", err
+			}
+			if closing != name {
+				return "", fmt.Errorf(`\begin{%s} closed by \end{%s}`, name, closing)
+			}
+			b.WriteString("</mtd></mtr></mtable>")
+			return "<mrow>" + fence(fences[0]) + b.String() + fence(fences[1]) + "</mrow>", nil
+		default:
+			return "", fmt.Errorf(`unclosed \begin{%s}`, name)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package contentmanager
+
+import "strings"
+
+// Neighbours are the posts published either side of a post, for previous and
+// next links. Either is nil at the ends of the listing.
+type Neighbours struct {
+	// Prev is the post published just before, and Next just after.
+	Prev *Post
+	Next *Post
+	// Scope names what the neighbours were chosen from, such as a tag or
+	// section, or is empty when they were chosen from every post.
+	Scope string
+}
+
+// NavigationScope limits the posts that previous and next links move
+// between.
+type NavigationScope string
+
+const (
+	// ScopeAll moves between every published post.
+	ScopeAll NavigationScope = ""
+	// ScopeSection moves between posts in the same section.
+	ScopeSection NavigationScope = "section"
+	// ScopeTag moves between posts sharing the post's first tag.
+	ScopeTag NavigationScope = "tag"
+)
+
+// Neighbours returns the posts the query selects that were published just
+// before and after the post with slug, whatever order the query sorts by.
+// Both are nil when the query doesn't select the post.
+func (q Query) Neighbours(slug string) Neighbours {
+	q.cm.RLock()
+	defer q.cm.RUnlock()
+
+	var neighbours Neighbours
+	index := q.cm.index
+	if index == nil {
+		return neighbours
+	}
+
+	matches := q.matches(index)
+	selected := func(position int) bool {
+		return matches == nil || matches[position]
+	}
+
+	current, ok := index.slugs[slug]
+	if !ok || !selected(current) {
+		return neighbours
+	}
+
+	// The index is newest first, so newer posts come before the post
+	for position := current - 1; position >= 0; position-- {
+		if selected(position) {
+			post := index.posts[position]
+			neighbours.Next = &post
+			break
+		}
+	}
+	for position := current + 1; position < len(index.posts); position++ {
+		if selected(position) {
+			post := index.posts[position]
+			neighbours.Prev = &post
+			break
+		}
+	}
+
+	return neighbours
+}
+
+// GetNeighbours returns the posts published either side of post, among every
+// post or only those sharing its section or first tag. Posts without a
+// section or tag fall back to every post.
+func (cm *ContentManager) GetNeighbours(post Post, scope NavigationScope) Neighbours {
+	query := cm.Query()
+	var name string
+
+	switch scope {
+	case ScopeSection:
+		if name = strings.TrimSpace(post.Section); name != "" {
+			query = query.Section(name)
+		}
+	case ScopeTag:
+		if len(post.Tags) > 0 {
+			name = post.Tags[0]
+			query = query.Tags(name)
+		}
+	}
+
+	neighbours := query.Neighbours(post.Slug)
+	neighbours.Scope = name
+	return neighbours
+}
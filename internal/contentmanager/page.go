@@ -0,0 +1,59 @@
+package contentmanager
+
+// Page is one page of a listing of posts.
+type Page struct {
+	Posts []Post
+	// Total is the number of posts in the whole listing.
+	Total  int
+	Offset int
+	Limit  int
+}
+
+// Paginate returns the page of posts starting at offset, holding up to limit
+// posts. A limit of zero or less puts every post on one page.
+func Paginate(posts []Post, offset, limit int) Page {
+	if limit <= 0 {
+		limit = max(len(posts), 1)
+	}
+	offset = min(max(offset, 0), len(posts))
+
+	return Page{
+		Posts:  posts[offset:min(offset+limit, len(posts))],
+		Total:  len(posts),
+		Offset: offset,
+		Limit:  limit,
+	}
+}
+
+// PageOffset is the offset of page number in a listing of limit posts a
+// page, numbered from 1.
+func PageOffset(number, limit int) int {
+	return (max(number, 1) - 1) * limit
+}
+
+// Number is the page's number, counting from 1.
+func (p Page) Number() int {
+	if p.Limit <= 0 {
+		return 1
+	}
+	return p.Offset/p.Limit + 1
+}
+
+// TotalPages is the number of pages in the listing, which is at least one
+// even when it's empty.
+func (p Page) TotalPages() int {
+	if p.Limit <= 0 {
+		return 1
+	}
+	return max((p.Total+p.Limit-1)/p.Limit, 1)
+}
+
+// HasPrev reports whether there is a page before this one.
+func (p Page) HasPrev() bool {
+	return p.Number() > 1
+}
+
+// HasNext reports whether there is a page after this one.
+func (p Page) HasNext() bool {
+	return p.Number() < p.TotalPages()
+}
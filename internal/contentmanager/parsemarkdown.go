@@ -3,37 +3,54 @@ package contentmanager
 import (
 	"bytes"
 	"github.com/spf13/viper"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/renderer/html"
 	"log"
-	// "regexp"
 	"strings"
 	"time"
 )
 
-func parseMarkdown(content string) (Post, error) {
+func parseMarkdown(content, path string, p *pipeline) (Post, error) {
 	fm, body, err := parseFrontMatter([]byte(content))
 	if err != nil {
 		return Post{}, err
 	}
 
-	output, err := markdownToHtml([]byte(body))
-	if err != nil {
-		return Post{}, err
-	}
-
-	return Post{
+	post := Post{
 		ID:          fm.ID,
 		Date:        fm.Date,
 		DisplayDate: fm.Date.Format(time.RFC3339),
+		Updated:     fm.LastMod,
 		Title:       fm.Title,
-		Content:     output,
+		Author:      fm.Author,
+		Summary:     fm.Summary,
 		RawContent:  body,
+		Path:        path,
 		Slug:        fm.Slug,
-		Tags:        fm.Tags,
+		Tags:        p.tags.Normalize(fm.Tags),
+		Categories:  parseCategories(fm.Categories),
+		Section:     strings.TrimSpace(fm.Section),
+		Series:      strings.TrimSpace(fm.Series),
+		SeriesOrder: fm.SeriesOrder,
+		Related:     fm.Related,
 		Published:   fm.Published,
-	}, nil
+		RawHTML:     fm.RawHTML,
+	}
+
+	// Posts without a section belong to their first top-level category
+	if post.Section == "" && len(post.Categories) > 0 {
+		post.Section = post.Categories[0][0]
+	}
+
+	// Posts that won't be served aren't rendered, so their assets aren't
+	// fetched either
+	if post.Slug == "" || !post.Published {
+		return post, nil
+	}
+
+	if err := p.render(&post, []byte(body)); err != nil {
+		return Post{}, err
+	}
+
+	return post, nil
 }
 
 func parseFrontMatter(markdown []byte) (FrontMatter, string, error) {
@@ -58,38 +75,8 @@ func parseFrontMatter(markdown []byte) (FrontMatter, string, error) {
 		return FrontMatter{}, "", err
 	}
 
-	log.Printf("Successfully parsed frontmatter: Title='%s', Slug='%s', Published=%v", 
+	log.Printf("Successfully parsed frontmatter: Title='%s', Slug='%s', Published=%v",
 		fm.Title, fm.Slug, fm.Published)
 
 	return fm, parts[2], nil
 }
-
-func markdownToHtml(markdown []byte) (string, error) {
-	md := goldmark.New(
-		goldmark.WithExtensions(
-			extension.GFM,
-		),
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			extension.Linkify,
-		),
-	)
-
-	var buf bytes.Buffer
-	if err := md.Convert(markdown, &buf); err != nil {
-		return "", err
-	}
-
-	output := string(buf.Bytes())
-
-	// Replace target="_blank" for links
-	output = strings.ReplaceAll(output, `<a href=`, `<a target="_blank" href=`)
-
-	// Note: Keep the original language-* classes for highlight.js
-	// Highlight.js expects: <code class="language-go">
-	// No modification needed since Goldmark already outputs this format
-
-	return output, nil
-}
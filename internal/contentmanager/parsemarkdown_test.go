@@ -0,0 +1,77 @@
+package contentmanager
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMarkdownSkipsUnservedPosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unpublished", "---\ntitle: Draft\nslug: draft\npublished: false\n---\n![Diagram](diagram.png)\n"},
+		{"no slug", "---\ntitle: Draft\npublished: true\n---\n![Diagram](diagram.png)\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cm := New("owner", "repo")
+			cm.client = &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+				t.Errorf("fetched %s for a post that isn't served", req.URL)
+				return nil, http.ErrNotSupported
+			})}
+
+			post, err := parseMarkdown(test.content, "draft.md", cm.newPipeline())
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			if post.Content != "" {
+				t.Errorf("rendered a post that isn't served:\n%s", post.Content)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	content := `---
+ID: post-1
+title: Part Three
+date: 2024-03-01T10:00:00Z
+lastmod: 2024-03-05T12:30:00Z
+slug: part-three
+series: Getting Started
+series_order: 3
+related: [part-one]
+published: true
+rawhtml: true
+---
+Body
+`
+
+	fm, body, err := parseFrontMatter([]byte(content))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if fm.SeriesOrder != 3 {
+		t.Errorf("SeriesOrder = %d, want 3", fm.SeriesOrder)
+	}
+	if fm.ID != "post-1" || fm.Slug != "part-three" || fm.Series != "Getting Started" {
+		t.Errorf("ID, Slug, Series = %q, %q, %q", fm.ID, fm.Slug, fm.Series)
+	}
+	if want := time.Date(2024, 3, 5, 12, 30, 0, 0, time.UTC); !fm.LastMod.Equal(want) {
+		t.Errorf("LastMod = %v, want %v", fm.LastMod, want)
+	}
+	if !fm.Published || !fm.RawHTML {
+		t.Errorf("Published, RawHTML = %v, %v, want both true", fm.Published, fm.RawHTML)
+	}
+	if len(fm.Related) != 1 || fm.Related[0] != "part-one" {
+		t.Errorf("Related = %q, want [part-one]", fm.Related)
+	}
+	if strings.TrimSpace(body) != "Body" {
+		t.Errorf("body = %q, want Body", body)
+	}
+}
@@ -0,0 +1,171 @@
+package contentmanager
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// HTMLTransformer rewrites a post's HTML after the markdown is rendered.
+type HTMLTransformer func(html []byte, post Post) ([]byte, error)
+
+// postKey holds the post being parsed in the parser.Context passed to AST
+// transformers.
+var postKey = parser.NewContextKey()
+
+// PostFromContext returns the post being parsed, with its frontmatter fields
+// and Path set, so AST transformers can depend on it.
+func PostFromContext(pc parser.Context) (Post, bool) {
+	post, ok := pc.Get(postKey).(Post)
+	return post, ok
+}
+
+// UseMarkdownExtension adds goldmark extensions to the markdown pipeline.
+// Like the other Use methods, call it before RefreshContent; posts are
+// parsed with the pipeline as it was when the refresh started.
+func (cm *ContentManager) UseMarkdownExtension(extensions ...goldmark.Extender) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.extensions = append(cm.extensions, extensions...)
+}
+
+// UseASTTransformer adds a transformer that runs on each post's parsed
+// markdown. Transformers with lower priorities run first.
+func (cm *ContentManager) UseASTTransformer(transformer parser.ASTTransformer, priority int) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.astTransformers = append(cm.astTransformers, util.Prioritized(transformer, priority))
+}
+
+// UseNodeRenderer adds a renderer for markdown nodes. Renderers with lower
+// priorities win over others for the same node kinds; goldmark's own HTML
+// renderer has priority 1000.
+func (cm *ContentManager) UseNodeRenderer(nodeRenderer renderer.NodeRenderer, priority int) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.nodeRenderers = append(cm.nodeRenderers, util.Prioritized(nodeRenderer, priority))
+}
+
+// UseHTMLTransformer adds a transformer that runs on each post's rendered
+// HTML, in the order they were added.
+func (cm *ContentManager) UseHTMLTransformer(transformer HTMLTransformer) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.htmlTransformers = append(cm.htmlTransformers, transformer)
+}
+
+// useDefaultPipeline registers the markdown features every site gets.
+func (cm *ContentManager) useDefaultPipeline() {
+	cm.UseMarkdownExtension(
+		extension.GFM,
+		&calloutExtension{},
+		&shortcodeExtension{registry: cm.shortcodes},
+		extension.Linkify,
+	)
+	cm.UseASTTransformer(&linkTransformer{}, 500)
+	cm.UseASTTransformer(&assetTransformer{cm: cm}, 500)
+	cm.UseNodeRenderer(&headingRenderer{}, 500)
+	cm.UseNodeRenderer(&codeBlockRenderer{}, 500)
+}
+
+// pipeline is a snapshot of the ContentManager's markdown configuration used
+// for one refresh.
+type pipeline struct {
+	markdown         goldmark.Markdown
+	htmlTransformers []HTMLTransformer
+	sanitizePolicy   *bluemonday.Policy
+	trustedSources   []string
+
+	// slugs maps the repository path of each post in the refresh to its
+	// slug, for resolving links between posts.
+	slugs map[string]string
+
+	// assets collects the files posts reference from the repository.
+	assets *assetCollector
+
+	// tags normalises the tags in each post's frontmatter.
+	tags *TagRegistry
+}
+
+// newPipeline builds the markdown parser and renderer from everything
+// registered with the Use methods.
+func (cm *ContentManager) newPipeline() *pipeline {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	return &pipeline{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(cm.extensions...),
+			goldmark.WithParserOptions(
+				parser.WithAutoHeadingID(),
+				parser.WithASTTransformers(cm.astTransformers...),
+			),
+			goldmark.WithRendererOptions(
+				html.WithUnsafe(),
+				renderer.WithNodeRenderers(cm.nodeRenderers...),
+			),
+		),
+		htmlTransformers: append([]HTMLTransformer(nil), cm.htmlTransformers...),
+		sanitizePolicy:   cm.sanitizePolicy,
+		trustedSources:   append([]string(nil), cm.trustedSources...),
+		slugs:            make(map[string]string),
+		assets: &assetCollector{
+			assets:   make(map[string]*Asset),
+			previous: cm.assets,
+			images:   cm.images,
+		},
+	}
+}
+
+// render converts the post's markdown body into its Content and TOC.
+func (p *pipeline) render(post *Post, body []byte) error {
+	pc := parser.NewContext()
+	pc.Set(postKey, *post)
+	pc.Set(slugsKey, p.slugs)
+	pc.Set(assetsKey, p.assets)
+
+	doc := p.markdown.Parser().Parse(text.NewReader(body), parser.WithContext(pc))
+	post.TOC = buildTOC(doc, body)
+
+	var buf bytes.Buffer
+	if err := p.markdown.Renderer().Render(&buf, body, doc); err != nil {
+		return err
+	}
+
+	output := buf.Bytes()
+	for _, transform := range p.htmlTransformers {
+		var err error
+		if output, err = transform(output, *post); err != nil {
+			return fmt.Errorf("failed to transform HTML: %w", err)
+		}
+	}
+
+	// Sanitise last so nothing added above can reintroduce unsafe markup
+	if post.RawHTML && isTrusted(post.Path, p.trustedSources) {
+		log.Printf("Skipping sanitiser for trusted post %s", post.Path)
+	} else {
+		if post.RawHTML {
+			log.Printf("Ignoring rawhtml in %s: not a trusted source", post.Path)
+		}
+		var stripped []string
+		output, stripped = sanitize(p.sanitizePolicy, output)
+		logStripped(post.Path, stripped)
+	}
+
+	post.Content = string(output)
+
+	return nil
+}
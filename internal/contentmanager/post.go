@@ -6,12 +6,33 @@ type Post struct {
 	ID          string    `yaml:"ID"`
 	Date        time.Time `yaml:"date"`
 	DisplayDate string
-	Title       string `yaml:"title"`
-	Author      string `yaml:"author"`
-	Summary     string `yaml:"summary"`
+	Updated     time.Time `yaml:"lastmod"`
+	Title       string    `yaml:"title"`
+	Author      string    `yaml:"author"`
+	Summary     string    `yaml:"summary"`
 	Content     string
 	RawContent  string
+	TOC         []TOCEntry
+	Path        string
 	Slug        string   `yaml:"slug"`
 	Tags        []string `yaml:"tags"`
+	Categories  []Category
+	Section     string   `yaml:"section"`
+	Series      string   `yaml:"series"`
+	SeriesOrder int      `yaml:"series_order"`
+	Related     []string `yaml:"related"`
 	Published   bool     `yaml:"published"`
+	RawHTML     bool     `yaml:"rawhtml"`
+}
+
+// IsUpdated reports whether the post was revised on a later day than it was
+// published.
+func (p Post) IsUpdated() bool {
+	return p.Updated.Truncate(24 * time.Hour).After(p.Date.Truncate(24 * time.Hour))
+}
+
+// ShowTOC reports whether the post is long enough to warrant a table of
+// contents.
+func (p Post) ShowTOC() bool {
+	return countTOC(p.TOC) >= tocMinHeadings
 }
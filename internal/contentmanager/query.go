@@ -0,0 +1,319 @@
+package contentmanager
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortOrder is the order a Query returns posts in.
+type SortOrder string
+
+const (
+	// SortNewest orders posts by publication date, newest first.
+	SortNewest SortOrder = "newest"
+	// SortOldest orders posts by publication date, oldest first.
+	SortOldest SortOrder = "oldest"
+	// SortUpdated orders posts by when they were last revised, most recent
+	// first.
+	SortUpdated SortOrder = "updated"
+	// SortTitle orders posts alphabetically by title.
+	SortTitle SortOrder = "title"
+)
+
+// postIndex holds the published posts in each sort order, which posts have
+// each tag, author, section, series and category, and the posts related to
+// each, so queries don't sort or scan every post. It is built once per
+// refresh and never modified.
+type postIndex struct {
+	// posts are newest first; everything else refers to posts by position.
+	posts   []Post
+	slugs   map[string]int
+	orders  map[SortOrder][]int
+	related [][]int
+
+	// The filter indices are keyed by lower case name and list positions in
+	// ascending order.
+	tags       map[string][]int
+	authors    map[string][]int
+	sections   map[string][]int
+	series     map[string][]int
+	categories map[string][]int
+}
+
+// buildIndex indexes the published posts.
+func buildIndex(posts map[string]Post) *postIndex {
+	index := &postIndex{
+		slugs:      make(map[string]int),
+		orders:     make(map[SortOrder][]int),
+		tags:       make(map[string][]int),
+		authors:    make(map[string][]int),
+		sections:   make(map[string][]int),
+		series:     make(map[string][]int),
+		categories: make(map[string][]int),
+	}
+
+	for _, post := range posts {
+		index.posts = append(index.posts, post)
+	}
+	sort.Slice(index.posts, func(i, j int) bool {
+		return newer(index.posts[i], index.posts[j])
+	})
+
+	newest := make([]int, len(index.posts))
+	for i := range newest {
+		newest[i] = i
+	}
+	index.orders[SortNewest] = newest
+
+	oldest := make([]int, len(index.posts))
+	for i := range oldest {
+		oldest[i] = len(index.posts) - 1 - i
+	}
+	index.orders[SortOldest] = oldest
+
+	updated := append([]int(nil), newest...)
+	sort.SliceStable(updated, func(i, j int) bool {
+		return index.posts[updated[i]].lastRevised().After(index.posts[updated[j]].lastRevised())
+	})
+	index.orders[SortUpdated] = updated
+
+	title := append([]int(nil), newest...)
+	sort.SliceStable(title, func(i, j int) bool {
+		return strings.ToLower(index.posts[title[i]].Title) < strings.ToLower(index.posts[title[j]].Title)
+	})
+	index.orders[SortTitle] = title
+
+	for i, post := range index.posts {
+		index.slugs[post.Slug] = i
+
+		for _, tag := range post.Tags {
+			addPosition(index.tags, tag, i)
+		}
+		addPosition(index.authors, post.Author, i)
+		addPosition(index.sections, post.Section, i)
+		addPosition(index.series, post.Series, i)
+
+		seen := make(map[string]bool)
+		for _, category := range post.Categories {
+			for depth := range category {
+				key := strings.ToLower(category[:depth+1].String())
+				if !seen[key] {
+					seen[key] = true
+					addPosition(index.categories, key, i)
+				}
+			}
+		}
+	}
+
+	index.related = relatedPositions(index.posts)
+
+	return index
+}
+
+// addPosition records that the post at position has name, skipping blank
+// names and repeats.
+func addPosition(positions map[string][]int, name string, position int) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return
+	}
+	if list := positions[key]; len(list) > 0 && list[len(list)-1] == position {
+		return
+	}
+	positions[key] = append(positions[key], position)
+}
+
+// newer orders posts newest first, then by slug so the order is stable.
+func newer(a, b Post) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.After(b.Date)
+	}
+	return a.Slug < b.Slug
+}
+
+// lastRevised is when the post last changed, which is its publication date
+// until it is updated.
+func (p Post) lastRevised() time.Time {
+	if p.Updated.After(p.Date) {
+		return p.Updated
+	}
+	return p.Date
+}
+
+// Query selects published posts. Each method returns a narrowed copy, so a
+// query can be built up and reused:
+//
+//	page := cm.Query().Tags("Go").Between(from, to).SortBy(SortTitle).Page()
+type Query struct {
+	cm       *ContentManager
+	tags     []string
+	author   string
+	from, to time.Time
+	section  string
+	series   string
+	category Category
+	sort     SortOrder
+	offset   int
+	limit    int
+}
+
+// Query starts a query over every published post, newest first.
+func (cm *ContentManager) Query() Query {
+	return Query{cm: cm, sort: SortNewest}
+}
+
+// Tags keeps posts that have every one of tags, or their aliases.
+func (q Query) Tags(tags ...string) Query {
+	q.tags = append(append([]string(nil), q.tags...), tags...)
+	return q
+}
+
+// Author keeps posts by author, ignoring case.
+func (q Query) Author(author string) Query {
+	q.author = author
+	return q
+}
+
+// Between keeps posts published from from up to, but not including, to.
+// Either may be zero to leave that end open.
+func (q Query) Between(from, to time.Time) Query {
+	q.from, q.to = from, to
+	return q
+}
+
+// Section keeps posts in section, ignoring case.
+func (q Query) Section(section string) Query {
+	q.section = section
+	return q
+}
+
+// Series keeps posts in series, ignoring case.
+func (q Query) Series(series string) Query {
+	q.series = series
+	return q
+}
+
+// Category keeps posts in category or its subcategories.
+func (q Query) Category(category Category) Query {
+	q.category = category
+	return q
+}
+
+// SortBy sets the order of the results. Unknown orders are newest first.
+func (q Query) SortBy(order SortOrder) Query {
+	q.sort = order
+	return q
+}
+
+// Offset skips the first n results.
+func (q Query) Offset(n int) Query {
+	q.offset = n
+	return q
+}
+
+// Limit returns at most n results. Zero, the default, returns them all.
+func (q Query) Limit(n int) Query {
+	q.limit = n
+	return q
+}
+
+// Posts returns the posts the query selects, after the offset and up to the
+// limit.
+func (q Query) Posts() []Post {
+	return q.Page().Posts
+}
+
+// Count returns the number of posts the query selects, ignoring the offset
+// and limit.
+func (q Query) Count() int {
+	return q.Page().Total
+}
+
+// Page returns the posts the query selects as a Page, with the total number
+// of matches for pagination.
+func (q Query) Page() Page {
+	q.cm.RLock()
+	defer q.cm.RUnlock()
+
+	index := q.cm.index
+	if index == nil {
+		return Paginate(nil, q.offset, q.limit)
+	}
+
+	matches := q.matches(index)
+
+	order, ok := index.orders[q.sort]
+	if !ok {
+		order = index.orders[SortNewest]
+	}
+
+	var posts []Post
+	for _, position := range order {
+		if matches == nil || matches[position] {
+			posts = append(posts, index.posts[position])
+		}
+	}
+
+	return Paginate(posts, q.offset, q.limit)
+}
+
+// matches returns the positions of the posts the query selects, or nil when
+// it selects every post. The caller must hold the lock.
+func (q Query) matches(index *postIndex) map[int]bool {
+	// Each indexed filter narrows the candidates to the posts listed for it
+	var candidates [][]int
+	for _, tag := range q.tags {
+		candidates = append(candidates, index.tags[strings.ToLower(q.cm.tags.Canonical(tag))])
+	}
+	if q.author != "" {
+		candidates = append(candidates, index.authors[strings.ToLower(strings.TrimSpace(q.author))])
+	}
+	if q.section != "" {
+		candidates = append(candidates, index.sections[strings.ToLower(strings.TrimSpace(q.section))])
+	}
+	if q.series != "" {
+		candidates = append(candidates, index.series[strings.ToLower(strings.TrimSpace(q.series))])
+	}
+	if len(q.category) > 0 {
+		candidates = append(candidates, index.categories[strings.ToLower(q.category.String())])
+	}
+
+	if len(candidates) == 0 && q.from.IsZero() && q.to.IsZero() {
+		return nil
+	}
+
+	// Start from the smallest list and keep positions every list has
+	counts := make(map[int]int)
+	if len(candidates) == 0 {
+		for position := range index.posts {
+			counts[position] = 0
+		}
+	} else {
+		sort.Slice(candidates, func(i, j int) bool { return len(candidates[i]) < len(candidates[j]) })
+		for _, position := range candidates[0] {
+			counts[position] = 0
+		}
+		for _, list := range candidates {
+			for _, position := range list {
+				if _, ok := counts[position]; ok {
+					counts[position]++
+				}
+			}
+		}
+	}
+
+	matches := make(map[int]bool)
+	for position, count := range counts {
+		if count < len(candidates) {
+			continue
+		}
+		date := index.posts[position].Date
+		if !q.from.IsZero() && date.Before(q.from) || !q.to.IsZero() && !date.Before(q.to) {
+			continue
+		}
+		matches[position] = true
+	}
+
+	return matches
+}
@@ -0,0 +1,202 @@
+package contentmanager
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxRelated is the most related posts kept for each post.
+	maxRelated = 10
+	// tagWeight and textWeight balance shared tags against similar wording
+	// when scoring how related two posts are. Both similarities run from 0
+	// to 1.
+	tagWeight  = 0.4
+	textWeight = 0.6
+)
+
+// stopWords are common English words left out when comparing the text of
+// posts, since they say nothing about what a post is about.
+var stopWords = map[string]bool{
+	"about": true, "after": true, "all": true, "also": true, "and": true, "any": true, "are": true,
+	"because": true, "been": true, "before": true, "but": true, "can": true, "could": true,
+	"did": true, "does": true, "each": true, "for": true, "from": true, "get": true, "has": true,
+	"have": true, "here": true, "how": true, "into": true, "its": true, "just": true, "like": true,
+	"more": true, "most": true, "not": true, "now": true, "one": true, "only": true, "other": true,
+	"our": true, "out": true, "over": true, "should": true, "some": true, "such": true, "than": true,
+	"that": true, "the": true, "their": true, "them": true, "then": true, "there": true,
+	"these": true, "they": true, "this": true, "those": true, "through": true, "use": true,
+	"using": true, "was": true, "way": true, "were": true, "what": true, "when": true,
+	"where": true, "which": true, "while": true, "who": true, "will": true, "with": true,
+	"would": true, "you": true, "your": true,
+}
+
+// terms splits text into lower case words for comparing posts, dropping
+// short words and stop words.
+func terms(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 && !stopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// termVectors weighs the words of each post by TF-IDF, so words that are
+// frequent in a post but rare across posts count the most. Each vector has
+// unit length, so the cosine similarity of two posts is their dot product.
+func termVectors(posts []Post) []map[string]float64 {
+	counts := make([]map[string]int, len(posts))
+	documents := make(map[string]int)
+	for i, post := range posts {
+		counts[i] = make(map[string]int)
+		for _, term := range terms(post.RawContent) {
+			if counts[i][term] == 0 {
+				documents[term]++
+			}
+			counts[i][term]++
+		}
+	}
+
+	vectors := make([]map[string]float64, len(posts))
+	for i, count := range counts {
+		vector := make(map[string]float64, len(count))
+		var norm float64
+		for term, n := range count {
+			// Words every post uses weigh nothing
+			weight := float64(n) * math.Log(float64(len(posts))/float64(documents[term]))
+			if weight > 0 {
+				vector[term] = weight
+				norm += weight * weight
+			}
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+// cosine is the cosine similarity of two unit length term vectors.
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot
+}
+
+// tagOverlap is the share of two posts' tags they have in common.
+func tagOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	tags := make(map[string]bool, len(a))
+	for _, tag := range a {
+		tags[strings.ToLower(tag)] = true
+	}
+
+	shared := 0
+	for _, tag := range b {
+		if tags[strings.ToLower(tag)] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// relatedPositions ranks, for each post in posts, the other posts most
+// related to it by shared tags and similar text. Posts listed in a post's
+// related frontmatter come first, in the order given.
+func relatedPositions(posts []Post) [][]int {
+	vectors := termVectors(posts)
+
+	positions := make(map[string]int, len(posts))
+	for i, post := range posts {
+		positions[strings.ToLower(post.Slug)] = i
+	}
+
+	type scored struct {
+		position int
+		score    float64
+	}
+
+	related := make([][]int, len(posts))
+	for i, post := range posts {
+		seen := map[int]bool{i: true}
+		for _, slug := range post.Related {
+			position, ok := positions[strings.ToLower(strings.TrimSpace(slug))]
+			if !ok {
+				log.Printf("Related post %q in %s is not a published post", slug, post.Path)
+				continue
+			}
+			if !seen[position] && len(related[i]) < maxRelated {
+				seen[position] = true
+				related[i] = append(related[i], position)
+			}
+		}
+
+		var candidates []scored
+		for j, other := range posts {
+			if seen[j] {
+				continue
+			}
+			score := tagWeight*tagOverlap(post.Tags, other.Tags) + textWeight*cosine(vectors[i], vectors[j])
+			if score > 0 {
+				candidates = append(candidates, scored{j, score})
+			}
+		}
+
+		// Posts are newest first, so equally related posts stay that way
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].score > candidates[b].score
+		})
+		for _, candidate := range candidates {
+			if len(related[i]) == maxRelated {
+				break
+			}
+			related[i] = append(related[i], candidate.position)
+		}
+	}
+
+	return related
+}
+
+// GetRelated returns up to limit posts related to the post with slug, most
+// related first.
+func (cm *ContentManager) GetRelated(slug string, limit int) []Post {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	if cm.index == nil {
+		return nil
+	}
+
+	position, ok := cm.index.slugs[slug]
+	if !ok {
+		return nil
+	}
+
+	var related []Post
+	for _, other := range cm.index.related[position] {
+		if len(related) == limit {
+			break
+		}
+		related = append(related, cm.index.posts[other])
+	}
+	return related
+}
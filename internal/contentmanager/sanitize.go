@@ -0,0 +1,178 @@
+package contentmanager
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// DefaultSanitizePolicy allows the markup posts are written in and the
+// markup the renderer adds for headings, code blocks, callouts, embeds,
+// footnotes, math and charts. Scripts, styles, forms, frames and event
+// handler attributes are removed.
+func DefaultSanitizePolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowStandardURLs()
+	p.AllowRelativeURLs(true)
+	p.RequireNoFollowOnLinks(false)
+
+	// Text and structure
+	p.AllowElements(
+		"a", "p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "pre", "code", "kbd", "samp",
+		"em", "strong", "b", "i", "u", "s", "del", "ins", "mark", "small", "sub", "sup", "abbr", "cite",
+		"q", "span", "div", "section", "aside", "figure", "figcaption", "details", "summary",
+		"ul", "ol", "li", "dl", "dt", "dd", "table", "thead", "tbody", "tfoot", "tr", "th", "td", "caption",
+	)
+	p.AllowAttrs("id", "class", "title", "role", "aria-label", "aria-hidden").Globally()
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("target").Matching(regexp.MustCompile(`^_blank$`)).OnElements("a")
+	p.AllowAttrs("rel").Matching(regexp.MustCompile(`^[a-z ]+$`)).OnElements("a")
+	p.AllowAttrs("start", "type").OnElements("ol")
+	p.AllowAttrs("scope", "colspan", "rowspan").OnElements("th", "td")
+	p.AllowStyles("text-align").MatchingEnum("left", "right", "center").OnElements("th", "td")
+	p.AllowAttrs("open").OnElements("details")
+
+	// Images, including the attributes responsive images need
+	p.AllowAttrs("src", "alt", "width", "height", "loading", "decoding", "srcset", "sizes").OnElements("img")
+	p.AllowAttrs("srcset", "sizes", "type", "media").OnElements("source")
+	p.AllowElements("img", "picture", "source")
+
+	// GFM task lists
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowElements("input")
+
+	// Code block copy buttons and click-to-load embeds
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^button$`)).OnElements("button")
+	p.AllowAttrs("data-copy-code").OnElements("button")
+	p.AllowAttrs("data-embed").Matching(regexp.MustCompile(`^(youtube|gist)$`)).OnElements("a")
+	p.AllowAttrs("data-embed-src").Matching(regexp.MustCompile(`^https://(www\.youtube-nocookie\.com|gist\.github\.com)/`)).OnElements("a")
+	p.AllowAttrs("data-embed-title").OnElements("a")
+	p.AllowElements("button")
+
+	// Icons and charts
+	p.AllowAttrs("xmlns", "viewbox", "width", "height", "fill", "stroke").OnElements("svg")
+	p.AllowElements("svg", "g", "path", "rect", "line", "polyline", "circle", "text", "title", "desc")
+	p.AllowNoAttrs().OnElements("g", "title", "desc")
+	p.AllowAttrs("fill", "fill-rule", "stroke", "stroke-width", "stroke-linecap", "stroke-linejoin", "transform").
+		OnElements("g", "path", "rect", "line", "polyline", "circle", "text")
+	p.AllowAttrs("d").OnElements("path")
+	p.AllowAttrs("x", "y", "width", "height", "rx").OnElements("rect")
+	p.AllowAttrs("x1", "x2", "y1", "y2").OnElements("line")
+	p.AllowAttrs("points").OnElements("polyline")
+	p.AllowAttrs("cx", "cy", "r").OnElements("circle")
+	p.AllowAttrs("x", "y", "text-anchor").OnElements("text")
+
+	// MathML
+	p.AllowAttrs("xmlns", "display").OnElements("math")
+	p.AllowNoAttrs().OnElements("math", "semantics", "mrow", "mi", "mo", "mn", "mtext", "mfrac",
+		"msqrt", "mroot", "msub", "msup", "msubsup", "munder", "mover", "munderover", "mtable", "mtr", "mtd")
+	p.AllowAttrs("encoding").OnElements("annotation")
+	p.AllowAttrs("mathvariant").OnElements("mi")
+	p.AllowAttrs("largeop", "fence", "stretchy").OnElements("mo")
+	p.AllowAttrs("linethickness").OnElements("mfrac")
+	p.AllowAttrs("accent").OnElements("mover")
+	p.AllowAttrs("width").OnElements("mspace")
+	p.AllowAttrs("columnalign", "columnspacing").OnElements("mtable")
+
+	return p
+}
+
+// SetSanitizePolicy replaces the allowlist applied to rendered posts.
+func (cm *ContentManager) SetSanitizePolicy(policy *bluemonday.Policy) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.sanitizePolicy = policy
+}
+
+// TrustSources lets posts whose repository path matches one of the patterns
+// opt out of sanitising with "rawhtml: true" in their frontmatter. Patterns
+// use path.Match syntax, such as "drafts/*.md", and may be comma separated.
+func (cm *ContentManager) TrustSources(patterns ...string) error {
+	var trusted []string
+	for _, pattern := range patterns {
+		for _, field := range strings.Split(pattern, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if _, err := path.Match(field, ""); err != nil {
+				return fmt.Errorf("invalid trusted source pattern %q: %w", field, err)
+			}
+			trusted = append(trusted, field)
+		}
+	}
+
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.trustedSources = append(cm.trustedSources, trusted...)
+
+	return nil
+}
+
+// isTrusted reports whether the post at postPath may opt into raw HTML.
+func isTrusted(postPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, postPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitize applies the policy to a post's HTML and describes what it
+// removed, such as "<script> x2" or "onclick on <a>".
+func sanitize(policy *bluemonday.Policy, input []byte) ([]byte, []string) {
+	output := policy.SanitizeBytes(input)
+
+	before, after := markupCounts(input), markupCounts(output)
+
+	var stripped []string
+	for item, count := range before {
+		if removed := count - after[item]; removed > 0 {
+			if removed > 1 {
+				item = fmt.Sprintf("%s x%d", item, removed)
+			}
+			stripped = append(stripped, item)
+		}
+	}
+	sort.Strings(stripped)
+
+	return output, stripped
+}
+
+// markupCounts counts the elements and attributes in an HTML fragment.
+func markupCounts(fragment []byte) map[string]int {
+	counts := make(map[string]int)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return counts
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			counts["<"+token.Data+">"]++
+			for _, attr := range token.Attr {
+				counts[fmt.Sprintf("%s on <%s>", attr.Key, token.Data)]++
+			}
+		}
+	}
+}
+
+// logStripped reports what sanitising removed from a post.
+func logStripped(postPath string, stripped []string) {
+	if len(stripped) > 0 {
+		log.Printf("Sanitiser removed from %s: %s", postPath, strings.Join(stripped, ", "))
+	}
+}
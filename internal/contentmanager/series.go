@@ -0,0 +1,92 @@
+package contentmanager
+
+import (
+	"sort"
+	"strings"
+)
+
+// Series is a set of posts meant to be read in order, such as a multi-part
+// tutorial. Posts join a series with the series frontmatter field and set
+// their place in it with series_order.
+type Series struct {
+	Name  string
+	Posts []Post
+}
+
+// Index returns the position of the post with slug in the series, or -1
+// when it isn't part of it.
+func (s Series) Index(slug string) int {
+	for i, post := range s.Posts {
+		if post.Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+// Prev returns the part before the post with slug.
+func (s Series) Prev(slug string) (Post, bool) {
+	if i := s.Index(slug); i > 0 {
+		return s.Posts[i-1], true
+	}
+	return Post{}, false
+}
+
+// Next returns the part after the post with slug.
+func (s Series) Next(slug string) (Post, bool) {
+	if i := s.Index(slug); i >= 0 && i < len(s.Posts)-1 {
+		return s.Posts[i+1], true
+	}
+	return Post{}, false
+}
+
+// seriesBefore orders the parts of a series by series_order, then by date
+// for parts that share an order. Parts without an order come last.
+func seriesBefore(a, b Post) bool {
+	if a.SeriesOrder != b.SeriesOrder {
+		if a.SeriesOrder == 0 || b.SeriesOrder == 0 {
+			return b.SeriesOrder == 0
+		}
+		return a.SeriesOrder < b.SeriesOrder
+	}
+	return newer(b, a)
+}
+
+// unifySeriesSpellings gives each series one name across all posts, so
+// parts written as "Kubernetes From Scratch" and "Kubernetes from scratch"
+// are in the same series.
+func unifySeriesSpellings(posts map[string]Post) {
+	uses := make(spellings)
+	for _, post := range posts {
+		if post.Series != "" {
+			uses.add(post.Series, post.Series)
+		}
+	}
+
+	for slug, post := range posts {
+		if post.Series != "" {
+			post.Series = uses.preferred(post.Series)
+			posts[slug] = post
+		}
+	}
+}
+
+// GetSeries returns a series, matched ignoring case, with its parts in
+// order.
+func (cm *ContentManager) GetSeries(name string) (Series, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Series{}, false
+	}
+
+	posts := cm.Query().Series(name).Posts()
+	if len(posts) == 0 {
+		return Series{}, false
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return seriesBefore(posts[i], posts[j])
+	})
+
+	return Series{Name: posts[0].Series, Posts: posts}, true
+}
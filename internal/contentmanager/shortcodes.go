@@ -0,0 +1,296 @@
+package contentmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ShortcodeArgs holds the arguments written after a shortcode's name, for
+// example {{< gist stratocraft 1234 file="main.go" >}}.
+type ShortcodeArgs struct {
+	Positional []string
+	Named      map[string]string
+
+	// Thumbnail is the URL of the shortcode's preview image, fetched during
+	// the refresh and served with the post's assets, or empty when the
+	// shortcode has none or it couldn't be fetched.
+	Thumbnail string
+}
+
+// Get returns the named argument, or the positional argument at index when
+// it wasn't given by name.
+func (a ShortcodeArgs) Get(name string, index int) string {
+	if value, ok := a.Named[name]; ok {
+		return value
+	}
+	if index >= 0 && index < len(a.Positional) {
+		return a.Positional[index]
+	}
+	return ""
+}
+
+// Shortcode builds the component rendered in place of a shortcode.
+type Shortcode func(args ShortcodeArgs) (templ.Component, error)
+
+// ShortcodeThumbnail returns the URL of a shortcode's preview image on a
+// third-party site, or false when it has none.
+type ShortcodeThumbnail func(args ShortcodeArgs) (string, bool)
+
+// ShortcodeRegistry maps shortcode names to the components they render.
+type ShortcodeRegistry struct {
+	sync.RWMutex
+	shortcodes map[string]Shortcode
+	thumbnails map[string]ShortcodeThumbnail
+}
+
+// NewShortcodeRegistry returns an empty registry.
+func NewShortcodeRegistry() *ShortcodeRegistry {
+	return &ShortcodeRegistry{
+		shortcodes: make(map[string]Shortcode),
+		thumbnails: make(map[string]ShortcodeThumbnail),
+	}
+}
+
+// Register adds or replaces the shortcode called name.
+func (r *ShortcodeRegistry) Register(name string, shortcode Shortcode) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.shortcodes[name] = shortcode
+}
+
+// Lookup returns the shortcode called name.
+func (r *ShortcodeRegistry) Lookup(name string) (Shortcode, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	shortcode, ok := r.shortcodes[name]
+	return shortcode, ok
+}
+
+// RegisterShortcode makes {{< name ... >}} available to posts. Register
+// shortcodes before calling RefreshContent so the first load expands them.
+func (cm *ContentManager) RegisterShortcode(name string, shortcode Shortcode) {
+	cm.shortcodes.Register(name, shortcode)
+}
+
+// RegisterThumbnail adds or replaces the preview image of the shortcode
+// called name.
+func (r *ShortcodeRegistry) RegisterThumbnail(name string, thumbnail ShortcodeThumbnail) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.thumbnails[name] = thumbnail
+}
+
+// Thumbnail returns the preview image URL of a shortcode.
+func (r *ShortcodeRegistry) Thumbnail(name string, args ShortcodeArgs) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	r.RLock()
+	thumbnail, ok := r.thumbnails[name]
+	r.RUnlock()
+
+	if !ok {
+		return "", false
+	}
+	return thumbnail(args)
+}
+
+// RegisterShortcodeThumbnail gives the shortcode called name a preview image
+// hosted elsewhere, such as a video's poster frame. The image is fetched when
+// posts are refreshed and served from the post's assets, passed to the
+// shortcode as ShortcodeArgs.Thumbnail, so readers' browsers never request it
+// from the third party.
+func (cm *ContentManager) RegisterShortcodeThumbnail(name string, thumbnail ShortcodeThumbnail) {
+	cm.shortcodes.RegisterThumbnail(name, thumbnail)
+}
+
+// ShortcodeNode is a {{< name args >}} shortcode in a post.
+type ShortcodeNode struct {
+	ast.BaseInline
+	Name string
+	Args ShortcodeArgs
+	Raw  string
+}
+
+// KindShortcode is the NodeKind of ShortcodeNode nodes.
+var KindShortcode = ast.NewNodeKind("Shortcode")
+
+// Kind implements ast.Node.
+func (n *ShortcodeNode) Kind() ast.NodeKind {
+	return KindShortcode
+}
+
+// Dump implements ast.Node.
+func (n *ShortcodeNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Name": n.Name}, nil)
+}
+
+// ShortcodeBlock is a shortcode written on its own line, rendered without a
+// wrapping paragraph so embeds can be block-level elements.
+type ShortcodeBlock struct {
+	ast.BaseBlock
+	Shortcode *ShortcodeNode
+}
+
+// KindShortcodeBlock is the NodeKind of ShortcodeBlock nodes.
+var KindShortcodeBlock = ast.NewNodeKind("ShortcodeBlock")
+
+// Kind implements ast.Node.
+func (n *ShortcodeBlock) Kind() ast.NodeKind {
+	return KindShortcodeBlock
+}
+
+// Dump implements ast.Node.
+func (n *ShortcodeBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Name": n.Shortcode.Name}, nil)
+}
+
+// shortcodeExtension expands {{< name args >}} shortcodes using the
+// components in a registry. Shortcodes inside code spans and code blocks are
+// left alone so posts can document them.
+type shortcodeExtension struct {
+	registry *ShortcodeRegistry
+}
+
+func (e *shortcodeExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&shortcodeParser{}, 90)),
+		parser.WithASTTransformers(util.Prioritized(&shortcodeBlockTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&shortcodeRenderer{registry: e.registry}, 500)),
+	)
+}
+
+var shortcodePattern = regexp.MustCompile(`^\{\{<\s*([A-Za-z][\w-]*)((?:\s+(?:[\w-]+=)?(?:"[^"]*"|'[^']*'|[^\s"'>]+))*)\s*>\}\}`)
+
+var shortcodeArgPattern = regexp.MustCompile(`(?:([\w-]+)=)?("[^"]*"|'[^']*'|[^\s"'>]+)`)
+
+type shortcodeParser struct{}
+
+func (p *shortcodeParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (p *shortcodeParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	match := shortcodePattern.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	block.Advance(len(match[0]))
+
+	return &ShortcodeNode{
+		Name: string(match[1]),
+		Args: parseShortcodeArgs(string(match[2])),
+		Raw:  string(match[0]),
+	}
+}
+
+func parseShortcodeArgs(input string) ShortcodeArgs {
+	args := ShortcodeArgs{Named: make(map[string]string)}
+
+	for _, match := range shortcodeArgPattern.FindAllStringSubmatch(input, -1) {
+		value := match[2]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'`)
+		}
+
+		if match[1] != "" {
+			args.Named[match[1]] = value
+		} else {
+			args.Positional = append(args.Positional, value)
+		}
+	}
+
+	return args
+}
+
+// shortcodeBlockTransformer lifts shortcodes that are alone in a paragraph
+// out of it.
+type shortcodeBlockTransformer struct{}
+
+func (t *shortcodeBlockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var paragraphs []*ast.Paragraph
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if para, ok := n.(*ast.Paragraph); ok && entering && para.ChildCount() == 1 {
+			if _, ok := para.FirstChild().(*ShortcodeNode); ok {
+				paragraphs = append(paragraphs, para)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, para := range paragraphs {
+		block := &ShortcodeBlock{Shortcode: para.FirstChild().(*ShortcodeNode)}
+		para.Parent().ReplaceChild(para.Parent(), para, block)
+	}
+}
+
+type shortcodeRenderer struct {
+	registry *ShortcodeRegistry
+}
+
+func (r *shortcodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindShortcode, r.renderShortcode)
+	reg.Register(KindShortcodeBlock, r.renderShortcode)
+}
+
+func (r *shortcodeRenderer) renderShortcode(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n, ok := node.(*ShortcodeNode)
+	if !ok {
+		n = node.(*ShortcodeBlock).Shortcode
+	}
+
+	if err := r.render(w, n); err != nil {
+		// Leave the shortcode visible so the broken embed is easy to spot
+		log.Printf("Failed to render shortcode %s: %v", n.Raw, err)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.Raw)))
+	}
+	if node.Kind() == KindShortcodeBlock {
+		_ = w.WriteByte('\n')
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *shortcodeRenderer) render(w util.BufWriter, n *ShortcodeNode) error {
+	shortcode, ok := r.registry.Lookup(n.Name)
+	if !ok {
+		return fmt.Errorf("unknown shortcode %q", n.Name)
+	}
+
+	component, err := shortcode(n.Args)
+	if err != nil {
+		return err
+	}
+
+	return component.Render(context.Background(), w)
+}
@@ -0,0 +1,85 @@
+package contentmanager
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// roundTripper answers HTTP requests without the network.
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestShortcodeThumbnail(t *testing.T) {
+	var poster bytes.Buffer
+	if err := jpeg.Encode(&poster, image.NewRGBA(image.Rect(0, 0, 4, 3)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{"fetched", http.StatusOK, `<img src="/posts/test/assets/thumbnails/`},
+		{"unavailable", http.StatusNotFound, `<span>no thumbnail</span>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requested []string
+			cm := New("owner", "repo")
+			cm.client = &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.String())
+				return &http.Response{
+					StatusCode: test.status,
+					Body:       io.NopCloser(bytes.NewReader(poster.Bytes())),
+					Header:     make(http.Header),
+				}, nil
+			})}
+
+			cm.RegisterShortcode("video", func(args ShortcodeArgs) (templ.Component, error) {
+				return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+					if args.Thumbnail == "" {
+						_, err := io.WriteString(w, "<span>no thumbnail</span>")
+						return err
+					}
+					_, err := io.WriteString(w, `<img src="`+args.Thumbnail+`">`)
+					return err
+				}), nil
+			})
+			cm.RegisterShortcodeThumbnail("video", func(args ShortcodeArgs) (string, bool) {
+				return "https://images.example.com/" + args.Get("id", 0) + ".jpg", true
+			})
+
+			p := cm.newPipeline()
+			post := Post{Path: "test.md", Slug: "test"}
+			if err := p.render(&post, []byte("{{< video abc >}}\n\nInline {{< video abc >}} too.\n")); err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+
+			if !strings.Contains(post.Content, test.want) {
+				t.Errorf("expected %s in:\n%s", test.want, post.Content)
+			}
+			if strings.Contains(post.Content, "images.example.com") {
+				t.Errorf("thumbnail linked from its host:\n%s", post.Content)
+			}
+			if len(requested) == 0 || requested[0] != "https://images.example.com/abc.jpg" {
+				t.Errorf("requested %v, want the thumbnail", requested)
+			}
+			if test.status == http.StatusOK && len(requested) != 1 {
+				t.Errorf("requested %v, want the thumbnail fetched once", requested)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package contentmanager
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tagRegistryFile is the file in the root of the posts repository that
+// defines the canonical tags.
+const tagRegistryFile = "tags.yaml"
+
+// TagInfo describes a canonical tag. Icon names a component in
+// internal/views/icons, such as "kubernetes".
+type TagInfo struct {
+	Name        string   `yaml:"name"`
+	Aliases     []string `yaml:"aliases"`
+	Description string   `yaml:"description"`
+	Icon        string   `yaml:"icon"`
+}
+
+// TagRegistry maps tags and their aliases, ignoring case, to canonical tags.
+// It is read from tags.yaml in the posts repository:
+//
+//	# tags.yaml
+//	- name: Kubernetes
+//	  aliases: [k8s, kube]
+//	  description: Running containers at scale.
+//	  icon: kubernetes
+type TagRegistry struct {
+	tags   []TagInfo
+	lookup map[string]int
+}
+
+// parseTagRegistry reads a tags.yaml file. A tag or alias defined twice is an
+// error, since posts using it couldn't be normalised.
+func parseTagRegistry(data []byte) (*TagRegistry, error) {
+	var tags []TagInfo
+	if err := yaml.Unmarshal(data, &tags); err != nil {
+		return nil, err
+	}
+
+	r := &TagRegistry{lookup: make(map[string]int)}
+	for _, tag := range tags {
+		tag.Name = strings.TrimSpace(tag.Name)
+		if tag.Name == "" {
+			return nil, fmt.Errorf("tag without a name")
+		}
+
+		index := len(r.tags)
+		for _, name := range append([]string{tag.Name}, tag.Aliases...) {
+			key := strings.ToLower(strings.TrimSpace(name))
+			// A blank alias would turn blank tags into this one
+			if key == "" {
+				continue
+			}
+			if existing, ok := r.lookup[key]; ok {
+				return nil, fmt.Errorf("%q is defined by both %s and %s", name, r.tags[existing].Name, tag.Name)
+			}
+			r.lookup[key] = index
+		}
+		r.tags = append(r.tags, tag)
+	}
+
+	return r, nil
+}
+
+// Lookup returns the canonical tag for a tag or one of its aliases.
+func (r *TagRegistry) Lookup(tag string) (TagInfo, bool) {
+	if r == nil {
+		return TagInfo{}, false
+	}
+
+	index, ok := r.lookup[strings.ToLower(strings.TrimSpace(tag))]
+	if !ok {
+		return TagInfo{}, false
+	}
+	return r.tags[index], true
+}
+
+// Canonical returns the canonical name of tag, or tag itself when it isn't
+// in the registry.
+func (r *TagRegistry) Canonical(tag string) string {
+	if info, ok := r.Lookup(tag); ok {
+		return info.Name
+	}
+	return strings.TrimSpace(tag)
+}
+
+// Normalize replaces tags with their canonical names and drops blanks and
+// duplicates, which may differ only in case.
+func (r *TagRegistry) Normalize(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = r.Canonical(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+// loadTagRegistry fetches tags.yaml when the posts repository has one.
+func (cm *ContentManager) loadTagRegistry(files []githubContent) (*TagRegistry, error) {
+	for _, file := range files {
+		if file.Type != "file" || file.Name != tagRegistryFile {
+			continue
+		}
+
+		content, err := cm.fetchFileContent(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", file.Name, err)
+		}
+
+		registry, err := parseTagRegistry([]byte(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", file.Name, err)
+		}
+
+		log.Printf("Loaded %d tags from %s", len(registry.tags), file.Name)
+		return registry, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,38 @@
+package contentmanager
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagRegistryNormalize(t *testing.T) {
+	r, err := parseTagRegistry([]byte("- name: Kubernetes\n  aliases: [k8s, '', ' ']\n- name: Go\n  aliases: [golang]\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"aliases", []string{"K8s", "golang"}, []string{"Kubernetes", "Go"}},
+		{"duplicates", []string{"kubernetes", "k8s", "Kubernetes"}, []string{"Kubernetes"}},
+		{"unknown", []string{"Terraform"}, []string{"Terraform"}},
+		{"blank", []string{"", " ", "go"}, []string{"Go"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := r.Normalize(test.tags); !slices.Equal(got, test.want) {
+				t.Errorf("Normalize(%q) = %q, want %q", test.tags, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagRegistryDuplicates(t *testing.T) {
+	if _, err := parseTagRegistry([]byte("- name: Go\n- name: Golang\n  aliases: [go]\n")); err == nil {
+		t.Error("expected an error for an alias defined twice")
+	}
+}
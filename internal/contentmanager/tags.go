@@ -0,0 +1,118 @@
+package contentmanager
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagCount is a tag and the number of published posts that have it, with
+// its description and icon when the tag registry defines it.
+type TagCount struct {
+	TagInfo
+	Count int
+}
+
+// GetTags returns every tag used by a published post, most used first and
+// then alphabetically.
+func (cm *ContentManager) GetTags() []TagCount {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	counts := make(map[string]*TagCount)
+	for _, post := range cm.posts {
+		for _, tag := range post.Tags {
+			key := strings.ToLower(tag)
+			if counts[key] == nil {
+				counts[key] = &TagCount{TagInfo: cm.tagInfo(tag)}
+			}
+			counts[key].Count++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, *count)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name)
+	})
+
+	return tags
+}
+
+// GetTag returns the canonical tag for tag or one of its aliases, if the tag
+// registry defines it or a published post uses it.
+func (cm *ContentManager) GetTag(tag string) (TagInfo, bool) {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	if info, ok := cm.tags.Lookup(tag); ok {
+		return info, true
+	}
+
+	for _, post := range cm.posts {
+		for _, t := range post.Tags {
+			if strings.EqualFold(t, strings.TrimSpace(tag)) {
+				return TagInfo{Name: t}, true
+			}
+		}
+	}
+
+	return TagInfo{}, false
+}
+
+// tagInfo describes a tag used by a post. The caller must hold the lock.
+func (cm *ContentManager) tagInfo(tag string) TagInfo {
+	if info, ok := cm.tags.Lookup(tag); ok {
+		return info
+	}
+	return TagInfo{Name: tag}
+}
+
+// unifyTagSpellings gives tags that aren't in the registry one spelling
+// across all posts.
+func unifyTagSpellings(posts map[string]Post) {
+	uses := make(spellings)
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			uses.add(tag, tag)
+		}
+	}
+
+	for slug, post := range posts {
+		for i, tag := range post.Tags {
+			post.Tags[i] = uses.preferred(tag)
+		}
+		posts[slug] = post
+	}
+}
+
+// spellings counts the ways names that differ only in case are written,
+// keyed by the lower case name.
+type spellings map[string]map[string]int
+
+func (s spellings) add(key, spelling string) {
+	key = strings.ToLower(key)
+	if s[key] == nil {
+		s[key] = make(map[string]int)
+	}
+	s[key][spelling]++
+}
+
+// preferred returns the most used spelling of key, or the first
+// alphabetically on a tie.
+func (s spellings) preferred(key string) string {
+	counts := s[strings.ToLower(key)]
+
+	var best string
+	for spelling, count := range counts {
+		if best == "" || count > counts[best] || count == counts[best] && spelling < best {
+			best = spelling
+		}
+	}
+	return best
+}
@@ -15,4 +15,28 @@ const (
 	PostRepoOwner string = "stratocraft"
 	// PostRepoName should be set to the name of the GitHub repo that has the posts in Markdown format.
 	PostRepoName string = "posts"
+	// SyntaxThemeLight is the chroma style used for code blocks in light mode.
+	SyntaxThemeLight string = "tokyonight-day"
+	// SyntaxThemeDark is the chroma style used for code blocks in dark mode.
+	SyntaxThemeDark string = "tokyonight-night"
+	// PostsPerPage is the number of posts on each page of the posts, tag, category and archive listings.
+	PostsPerPage int = 12
+	// MarkdownExtensions is a comma separated list of the optional markdown features enabled for posts:
+	// footnotes, definitionlists, typographer, emoji, abbreviations and math.
+	MarkdownExtensions string = "footnotes,definitionlists,typographer,emoji,abbreviations,math"
+	// TrustedSources is a comma separated list of path patterns in the posts repo, such as "pages/*.md", whose posts
+	// may skip HTML sanitising by setting rawhtml: true in their frontmatter. Leave empty to sanitise every post.
+	TrustedSources string = ""
+	// ImageWidths is a comma separated list of the widths in pixels that post images are resized to for srcset.
+	// Leave empty to serve images at their original size.
+	ImageWidths string = "480,960,1440,1920"
+	// ImageSizes is the sizes attribute of post images, matching the width of the post column.
+	ImageSizes string = "(min-width: 56rem) 56rem, 100vw"
+	// ImageCacheDir is where resized post images are kept between restarts.
+	ImageCacheDir string = "cache/images"
+	// PostNavigationScope limits the previous and next links under each post to posts in the same "section" or
+	// sharing its first "tag". Leave empty to move between every post.
+	PostNavigationScope string = ""
+	// RelatedPosts is the number of related posts shown under each post.
+	RelatedPosts int = 3
 )
@@ -0,0 +1,239 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Chart is a bar or line chart of one or more series sharing the same
+// category labels.
+type Chart struct {
+	Type   string
+	Title  string
+	XLabel string
+	YLabel string
+	Labels []string
+	Series []Series
+}
+
+// Series is a named set of values, one per label.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Chart dimensions in SVG user units. The SVG scales to the content width.
+const (
+	width        = 640
+	height       = 360
+	marginTop    = 16
+	marginRight  = 16
+	marginBottom = 56
+	marginLeft   = 64
+	plotWidth    = width - marginLeft - marginRight
+	plotHeight   = height - marginTop - marginBottom
+	tickCount    = 5
+)
+
+// seriesColors are the Tailwind fill and stroke classes for each series, in
+// order.
+var seriesColors = []string{
+	"fill-indigo-500 stroke-indigo-500",
+	"fill-emerald-500 stroke-emerald-500",
+	"fill-amber-500 stroke-amber-500",
+	"fill-rose-500 stroke-rose-500",
+	"fill-sky-500 stroke-sky-500",
+}
+
+func seriesColor(i int) string {
+	return seriesColors[i%len(seriesColors)]
+}
+
+// scale maps values onto the plot's vertical axis.
+type scale struct {
+	min, max, step float64
+}
+
+// newScale picks a range covering the chart's values, including zero, with
+// round tick intervals.
+func newScale(c Chart) scale {
+	lo, hi := 0.0, 0.0
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	step := niceStep((hi - lo) / tickCount)
+	return scale{
+		min:  math.Floor(lo/step) * step,
+		max:  math.Ceil(hi/step) * step,
+		step: step,
+	}
+}
+
+// niceStep rounds a raw tick interval to 1, 2, 5 or 10 times a power of ten.
+func niceStep(raw float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch fraction := raw / magnitude; {
+	case fraction <= 1:
+		return magnitude
+	case fraction <= 2:
+		return 2 * magnitude
+	case fraction <= 5:
+		return 5 * magnitude
+	default:
+		return 10 * magnitude
+	}
+}
+
+// y returns the vertical position of v.
+func (s scale) y(v float64) float64 {
+	return marginTop + plotHeight*(s.max-v)/(s.max-s.min)
+}
+
+// ticks returns the values labelled on the vertical axis.
+func (s scale) ticks() []float64 {
+	var ticks []float64
+	for v := s.min; v <= s.max+s.step/2; v += s.step {
+		ticks = append(ticks, math.Round(v/s.step)*s.step)
+	}
+	return ticks
+}
+
+// format writes a tick value with only as many decimals as the step needs.
+func (s scale) format(v float64) string {
+	decimals := 0
+	if s.step < 1 {
+		decimals = int(math.Ceil(-math.Log10(s.step)))
+	}
+	return formatNumber(v, decimals)
+}
+
+func formatNumber(v float64, decimals int) string {
+	if decimals < 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// slot returns the left edge and width of the i-th label's column.
+func (c Chart) slot(i int) (float64, float64) {
+	w := float64(plotWidth) / float64(len(c.Labels))
+	return marginLeft + w*float64(i), w
+}
+
+// center returns the horizontal middle of the i-th label's column.
+func (c Chart) center(i int) float64 {
+	x, w := c.slot(i)
+	return x + w/2
+}
+
+// bar is a rectangle for one value in a bar chart.
+type bar struct {
+	X, Y, Width, Height float64
+	Series              int
+	Label               string
+}
+
+// bars lays out each series' values side by side within the label columns.
+func (c Chart) bars(s scale) []bar {
+	var bars []bar
+	zero := s.y(math.Max(s.min, 0))
+
+	for i, label := range c.Labels {
+		x, w := c.slot(i)
+		group := w * 0.8
+		barWidth := group / float64(len(c.Series))
+
+		for j, series := range c.Series {
+			if i >= len(series.Values) {
+				continue
+			}
+			v := s.y(series.Values[i])
+			bars = append(bars, bar{
+				X:      x + (w-group)/2 + barWidth*float64(j),
+				Y:      math.Min(v, zero),
+				Width:  barWidth,
+				Height: math.Abs(zero - v),
+				Series: j,
+				Label:  fmt.Sprintf("%s, %s: %s", label, series.Name, formatNumber(series.Values[i], -1)),
+			})
+		}
+	}
+
+	return bars
+}
+
+// point is one value in a line chart.
+type point struct {
+	X, Y  float64
+	Label string
+}
+
+// points returns the positions of a series' values in a line chart.
+func (c Chart) points(s scale, series Series) []point {
+	var points []point
+	for i, v := range series.Values {
+		if i >= len(c.Labels) {
+			break
+		}
+		points = append(points, point{
+			X:     c.center(i),
+			Y:     s.y(v),
+			Label: fmt.Sprintf("%s, %s: %s", c.Labels[i], series.Name, formatNumber(v, -1)),
+		})
+	}
+	return points
+}
+
+// polyline returns the points attribute joining a series' values.
+func polyline(points []point) string {
+	var attr []byte
+	for i, p := range points {
+		if i > 0 {
+			attr = append(attr, ' ')
+		}
+		attr = strconv.AppendFloat(attr, round(p.X), 'f', -1, 64)
+		attr = append(attr, ',')
+		attr = strconv.AppendFloat(attr, round(p.Y), 'f', -1, 64)
+	}
+	return string(attr)
+}
+
+// round trims coordinates to two decimals to keep the markup small.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func coord(v float64) string {
+	return strconv.FormatFloat(round(v), 'f', -1, 64)
+}
+
+// description summarises the chart for screen readers.
+func (c Chart) description() string {
+	kind := "Bar chart"
+	if c.Type == "line" {
+		kind = "Line chart"
+	}
+	desc := fmt.Sprintf("%s of %d values", kind, len(c.Labels))
+	if c.XLabel != "" {
+		desc += " by " + c.XLabel
+	}
+	if c.YLabel != "" {
+		desc += ", measuring " + c.YLabel
+	}
+	return desc + ". The data is in the table that follows."
+}
+
+func value(series Series, i int) string {
+	if i >= len(series.Values) {
+		return ""
+	}
+	return formatNumber(series.Values[i], -1)
+}
@@ -0,0 +1,49 @@
+package icons
+
+import (
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// byName maps the names used in the posts repository's tags.yaml to icons.
+var byName = map[string]func() templ.Component{
+	"ansible":    Ansible,
+	"argo":       Argo,
+	"aws":        Aws,
+	"azure":      Azure,
+	"containerd": Containerd,
+	"docker":     Docker,
+	"flux":       FluxCD,
+	"gcp":        Gcp,
+	"git":        Git,
+	"github":     GitHub,
+	"gitlab":     GitLab,
+	"go":         Go,
+	"grafana":    Grafana,
+	"htmx":       Htmx,
+	"kubernetes": Kubernetes,
+	"leetcode":   LeetCode,
+	"linux":      Linux,
+	"macos":      Macos,
+	"microsoft":  Microsoft,
+	"neovim":     Neovim,
+	"odin":       Odin,
+	"prometheus": Prometheus,
+	"python":     Python,
+	"rhel":       Rhel,
+	"shell":      Shell,
+	"sql":        Sql,
+	"terraform":  Terraform,
+	"ubuntu":     Ubuntu,
+	"zig":        Zig,
+}
+
+// ByName returns the icon called name, ignoring case.
+func ByName(name string) (templ.Component, bool) {
+	icon, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, false
+	}
+	return icon(), true
+}
@@ -0,0 +1,21 @@
+// Copy-to-clipboard buttons for highlighted code blocks
+document.addEventListener('click', function(event) {
+    const button = event.target.closest('[data-copy-code]');
+    if (!button) {
+        return;
+    }
+
+    // Copy the code as it reads after the change, without line numbers or removed diff lines
+    const block = button.closest('.code-block');
+    const lines = block.querySelectorAll('.line:not(.diff-del) .cl');
+    const code = Array.from(lines, line => line.textContent).join('');
+
+    navigator.clipboard.writeText(code).then(function() {
+        button.textContent = 'Copied!';
+        setTimeout(function() {
+            button.textContent = 'Copy';
+        }, 2000);
+    }).catch(function(err) {
+        console.log('Failed to copy code:', err);
+    });
+});
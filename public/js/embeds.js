@@ -0,0 +1,33 @@
+// Load third-party embeds only once the reader clicks on them
+document.addEventListener('click', function(event) {
+    const link = event.target.closest('[data-embed]');
+    if (!link) {
+        return;
+    }
+    event.preventDefault();
+
+    const frame = document.createElement('iframe');
+    frame.title = link.dataset.embedTitle;
+    frame.loading = 'lazy';
+
+    switch (link.dataset.embed) {
+        case 'youtube':
+            frame.src = link.dataset.embedSrc;
+            frame.allow = 'accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture';
+            frame.allowFullscreen = true;
+            frame.className = 'aspect-video w-full rounded-lg';
+            break;
+        case 'gist':
+            // Gists are embedded with document.write, so give them their own document
+            const script = document.createElement('script');
+            script.src = link.dataset.embedSrc;
+            frame.srcdoc = '<base target="_blank">' + script.outerHTML;
+            frame.sandbox = 'allow-scripts allow-popups';
+            frame.className = 'w-full h-96 rounded-lg';
+            break;
+        default:
+            return;
+    }
+
+    link.replaceWith(frame);
+});
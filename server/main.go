@@ -26,9 +26,25 @@ func main() {
 	// Routes
 	e.GET("/", app.Home)
 	e.GET("/posts", app.PostsList)
+	e.GET("/posts/page/:page", app.PostsPage)
 	e.GET("/search", app.Search)
 	e.GET("/posts/:slug", app.PostDetail)
+	e.GET("/posts/:slug/history", app.PostHistory)
+	e.GET("/posts/:slug/assets/*", app.PostAsset)
+	e.GET("/tags", app.TagsIndex)
+	e.GET("/tags/:tag", app.TagPosts)
+	e.GET("/tags/:tag/page/:page", app.TagPosts)
+	e.GET("/categories", app.CategoriesIndex)
+	e.GET("/categories/*", app.CategoryPosts)
+	e.GET("/series/:name", app.SeriesPosts)
+	e.GET("/archive", app.ArchiveIndex)
+	e.GET("/archive/:year", app.ArchiveYear)
+	e.GET("/archive/:year/:month", app.ArchiveMonth)
+	e.GET("/archive/:year/page/:page", app.ArchiveYear)
+	e.GET("/archive/:year/:month/page/:page", app.ArchiveMonth)
 	e.GET("/about", app.About)
+	e.GET("/sitemap.xml", app.Sitemap)
+	e.GET("/css/syntax.css", app.SyntaxCSS)
 	
 	// Webhook for automatic content updates
 	e.POST("/webhook/github", app.WebhookHandler)